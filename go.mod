@@ -4,11 +4,24 @@ go 1.16
 
 require (
 	github.com/fullstorydev/grpcurl v1.8.0
+	github.com/go-sql-driver/mysql v1.6.0
+	github.com/golang/protobuf v1.5.0
+	github.com/google/cel-go v0.7.3
+	github.com/gorilla/websocket v1.4.2
 	github.com/grpc-ecosystem/grpc-gateway v1.9.2
 	github.com/jhump/protoreflect v1.6.1
-	github.com/stretchr/testify v1.7.0 // indirect
+	github.com/lib/pq v1.10.0
+	github.com/linkedin/goavro/v2 v2.9.8
+	github.com/mattn/go-sqlite3 v1.14.6
+	github.com/nats-io/nats.go v1.11.0
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/segmentio/kafka-go v0.4.47
+	github.com/stretchr/testify v1.8.0
+	github.com/xeipuuv/gojsonschema v1.2.0
+	go.starlark.net v0.0.0-20231101134539-556fd59b42f6
 	google.golang.org/grpc v1.36.0
 	google.golang.org/grpc/examples v0.0.0-20210318230139-bce1cded4b05
+	google.golang.org/protobuf v1.27.1
 	k8s.io/api v0.0.0-20210306132658-3687c906b8c9
 	k8s.io/apimachinery v0.0.0-20210306132128-283a3268598b
 	k8s.io/client-go v0.0.0-20210306133319-1745c9faaaff
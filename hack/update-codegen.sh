@@ -29,4 +29,10 @@ bash $(dirname "${BASH_SOURCE[0]}")/generate-groups.sh "deepcopy,client,informer
   --go-header-file "${SCRIPT_ROOT}"/hack/boilerplate.go.txt
 #  --output-base "$(dirname "${BASH_SOURCE[0]}")/.." \
 
+# action models are not a group version, so generate-groups.sh doesn't see them
+"$(go env GOPATH)"/bin/deepcopy-gen \
+  --input-dirs github.com/d7561985/karness/pkg/apis/karness/v1alpha1/models/action \
+  -O zz_generated.deepcopy --bounding-dirs github.com/d7561985/karness/pkg/apis \
+  --go-header-file "${SCRIPT_ROOT}"/hack/boilerplate.go.txt
+
 cp -R $(go env GOPATH)/src/github.com/d7561985/karness/pkg/ $SCRIPT_ROOT/pkg/
\ No newline at end of file
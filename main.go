@@ -1,10 +1,19 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/d7561985/karness/pkg/controllers/harness"
 	"github.com/d7561985/karness/pkg/controllers/kube"
+	"github.com/d7561985/karness/pkg/executor/callbackexec"
+	"github.com/d7561985/karness/pkg/executor/pluginexec"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
@@ -14,13 +23,44 @@ import (
 )
 
 var (
-	masterURL  string
-	kubeconfig string
+	masterURL        string
+	kubeconfig       string
+	plugins          = pluginFlag{}
+	callbackAddr     string
+	callbackURL      string
+	kubeAnyNamespace bool
 )
 
+// pluginFlag collects repeated -plugin name=target flags
+type pluginFlag map[string]string
+
+func (p pluginFlag) String() string {
+	res := make([]string, 0, len(p))
+	for name, target := range p {
+		res = append(res, name+"="+target)
+	}
+
+	return strings.Join(res, ",")
+}
+
+func (p pluginFlag) Set(v string) error {
+	kv := strings.SplitN(v, "=", 2)
+	if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+		return fmt.Errorf("plugin should be name=addr or name=exec:path, got %q", v)
+	}
+
+	p[kv[0]] = kv[1]
+
+	return nil
+}
+
 func init() {
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	flag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	flag.Var(plugins, "plugin", "Executor plugin as name=addr for running plugin or name=exec:path to start plugin binary. Could be repeated.")
+	flag.StringVar(&callbackAddr, "callback-addr", "", "Listen address of callback listener, e.g. :8090. Callback actions are disabled when empty.")
+	flag.StringVar(&callbackURL, "callback-url", "", "Base url of callback listener reachable by tested services, e.g. http://karness.karness.svc:8090.")
+	flag.BoolVar(&kubeAnyNamespace, "kube-any-namespace", false, "Allow kube actions to operate objects outside of scenario namespace with controller permissions.")
 }
 
 func main() {
@@ -35,15 +75,60 @@ func main() {
 		klog.Fatalf("Error building kubeconfig: %s", err.Error())
 	}
 
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building kubernetes clientset: %s", err.Error())
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building dynamic client: %s", err.Error())
+	}
+
 	client, err := clientset.NewForConfig(cfg)
 	if err != nil {
 		klog.Fatalf("Error building example clientset: %s", err.Error())
 	}
 
+	for name, target := range plugins {
+		p, err := pluginexec.Open(context.Background(), target)
+		if err != nil {
+			klog.Fatalf("Error loading plugin %s: %s", name, err.Error())
+		}
+
+		klog.Infof("plugin %s loaded: %s %s", name, p.Info.GetName(), p.Info.GetVersion())
+		pluginexec.Register(name, p)
+	}
+
+	defer pluginexec.CloseAll()
+
+	var opt []harness.Option
+
+	if kubeAnyNamespace {
+		opt = append(opt, harness.WithKubeAnyNamespace())
+	}
+
+	if callbackAddr != "" {
+		if callbackURL == "" {
+			klog.Fatal("callback-url is required along with callback-addr")
+		}
+
+		callbacks := callbackexec.New(callbackURL)
+		opt = append(opt, harness.WithCallbacks(callbacks))
+
+		go func() {
+			if err := http.ListenAndServe(callbackAddr, callbacks); err != nil {
+				klog.Fatalf("Error running callback listener: %s", err.Error())
+			}
+		}()
+	}
+
 	informerFactory := informers.NewSharedInformerFactory(client, time.Second*30)
 
-	c := kube.New(client,
-		informerFactory.Karness().V1alpha1().Scenarios())
+	c := kube.New(kubeClient, dynamicClient, client,
+		informerFactory.Karness().V1alpha1().Scenarios(),
+		informerFactory.Karness().V1alpha1().MockServices(),
+		opt...)
 
 	informerFactory.Start(stopCh)
 
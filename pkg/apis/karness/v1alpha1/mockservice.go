@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1/models/action"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Serving is state of mock service which servers are running
+const Serving State = "SERVING"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MockService runs stub grpc and http servers inside controller which answer with canned responses
+// and record calls, scenarios assert recorded calls with mock action
+type MockService struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status MockServiceStatus `json:"status"`
+	Spec   MockServiceSpec   `json:"spec"`
+}
+
+// MockServiceSpec at least one of GRPC and HTTP should be set
+type MockServiceSpec struct {
+	GRPC *MockGRPC `json:"grpc"`
+	HTTP *MockHTTP `json:"http"`
+
+	// Rules are checked in order, the first matched rule answers
+	Rules []MockRule `json:"rules"`
+}
+
+// MockGRPC server serves services of descriptors
+type MockGRPC struct {
+	// Port of server, random when zero
+	Port int32 `json:"port"`
+
+	// required: true
+	Descriptors action.Descriptors `json:"descriptors"`
+}
+
+type MockHTTP struct {
+	// Port of server, random when zero
+	Port int32 `json:"port"`
+}
+
+type MockRule struct {
+	// Method grpc "package.Service/Method" or http "METHOD /path"
+	// required: true
+	Method string `json:"method"`
+
+	// Match selects requests which fields have expected values
+	// Key: json path
+	// Val: expected value
+	Match map[string]string `json:"match"`
+
+	Response MockResponse `json:"response"`
+}
+
+type MockResponse struct {
+	// Code grpc code name, e.g. NotFound, or http status, OK and 200 by default
+	Code string `json:"code"`
+
+	// Body of response, grpc status message is taken from body when code isn't OK
+	Body Body `json:"body"`
+
+	// Header of http response or grpc response metadata
+	Header map[string]string `json:"header"`
+
+	// Delay of response
+	Delay metav1.Duration `json:"delay"`
+}
+
+// MockServiceStatus contains listen addresses of running servers
+type MockServiceStatus struct {
+	State State  `json:"state"`
+	GRPC  string `json:"grpc,omitempty"`
+	HTTP  string `json:"http,omitempty"`
+	// Message explains failure of mock service
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type MockServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MockService `json:"items"`
+}
@@ -0,0 +1,67 @@
+package action
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// Broker action publishes action body to topic or waits for message from topic.
+// Only one of Publish and Consume should be set
+type Broker struct {
+	// Kind kafka or nats
+	// required: true
+	Kind string `json:"kind"`
+
+	// Addr comma separated kafka brokers or nats url
+	// required: true
+	Addr string `json:"addr"`
+
+	// Topic of kafka or subject of nats
+	// required: true
+	Topic string `json:"topic"`
+
+	Publish *Publish `json:"publish"`
+
+	// Consume puts message value into result body and message headers into result header
+	Consume *Consume `json:"consume"`
+}
+
+type Publish struct {
+	// Key of kafka message
+	Key string `json:"key"`
+
+	Header map[string]string `json:"header"`
+}
+
+type Consume struct {
+	// Filter selects message which value matches all fields
+	// Key: json path
+	// Val: expected value
+	Filter map[string]string `json:"filter"`
+
+	// Timeout of waiting, 30s by default
+	Timeout metav1.Duration `json:"timeout"`
+
+	// FromBeginning reads messages retained by kafka, only new messages are read by default
+	FromBeginning bool `json:"from_beginning"`
+
+	// Decode converts binary message value to JSON before filter
+	Decode *Decode `json:"decode"`
+}
+
+// Decode binary message, only one of Protobuf and Avro should be set
+type Decode struct {
+	// Protobuf fully qualified message type, Descriptors are required
+	Protobuf string `json:"protobuf"`
+
+	Descriptors *Descriptors `json:"descriptors"`
+
+	Avro *Avro `json:"avro"`
+}
+
+// Avro schemas are resolved with schema registry
+type Avro struct {
+	// Registry url of schema registry compatible endpoint
+	// required: true
+	Registry string `json:"registry"`
+
+	// Subject which latest schema decodes messages without confluent wire format header
+	Subject string `json:"subject"`
+}
@@ -0,0 +1,24 @@
+package action
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// Callback action waits for request to endpoint of karness callback listener.
+// Endpoint is registered when scenario starts and its URL is stored in Variable,
+// so earlier actions are able to pass it to services.
+// Result body is request body, result header is request headers.
+type Callback struct {
+	// Variable of scenario which contains callback url
+	// required: true
+	Variable string `json:"variable"`
+
+	// Method of expected request, any by default
+	Method string `json:"method"`
+
+	// Match selects request which body fields have expected values
+	// Key: json path
+	// Val: expected value
+	Match map[string]string `json:"match"`
+
+	// Timeout of waiting, 30s by default, result code is DeadlineExceeded when it's exceeded
+	Timeout metav1.Duration `json:"timeout"`
+}
@@ -0,0 +1,4 @@
+// +k8s:deepcopy-gen=package
+
+// Package action contains action models used by Scenario events
+package action
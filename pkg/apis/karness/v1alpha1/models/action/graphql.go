@@ -0,0 +1,24 @@
+package action
+
+import "k8s.io/apimachinery/pkg/runtime"
+
+// GraphQL action posts operation to graphql endpoint.
+// Result body is {"data": ..., "errors": [...]}, code is OK or ERRORS when response contains errors.
+type GraphQL struct {
+	// Addr full url of graphql endpoint
+	// required: true
+	Addr string `json:"addr"`
+
+	// Header contains request headers
+	Header map[string]string `json:"header"`
+
+	// Query document
+	// required: true
+	Query string `json:"query"`
+
+	// OperationName selects operation of document
+	OperationName string `json:"operation_name"`
+
+	// Variables of operation, string values are templates rendered with scenario variables, e.g. "{{ .USER_ID }}"
+	Variables runtime.RawExtension `json:"variables"`
+}
@@ -1,5 +1,10 @@
 package action
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
 type GRPC struct {
 	// required: true
 	Addr string `json:"addr"`
@@ -13,4 +18,60 @@ type GRPC struct {
 
 	// rpc command
 	RPC string `json:"rpc"`
+
+	// Stream turns on streaming call, result body becomes json array of received messages
+	Stream *Stream `json:"stream"`
+
+	// Descriptors used along with server reflection, required when reflection is disabled
+	Descriptors *Descriptors `json:"descriptors"`
+
+	// TLS turns on secure connection, plaintext is used by default
+	TLS *TLS `json:"tls"`
+}
+
+// TLS configure secure connection
+type TLS struct {
+	// Secret name in scenario namespace with PEM encoded ca.crt, tls.crt and tls.key.
+	// ca.crt verifies server certificate, tls.crt and tls.key are client certificate for mTLS.
+	// All keys are optional
+	Secret string `json:"secret"`
+
+	// Insecure skips verification of server certificate
+	Insecure bool `json:"insecure"`
+
+	// ServerName overrides name used for verification of server certificate
+	ServerName string `json:"server_name"`
+
+	// Authority overrides :authority pseudo header
+	Authority string `json:"authority"`
+}
+
+// Descriptors define sources of proto descriptors
+type Descriptors struct {
+	// Protoset paths of compiled descriptor sets (protoc --descriptor_set_out), e.g. on mounted volume
+	Protoset []string `json:"protoset"`
+
+	// Proto paths of .proto sources, resolved relative to ImportPath
+	Proto []string `json:"proto"`
+
+	// ImportPath directories for resolving of Proto files and their imports
+	ImportPath []string `json:"import_path"`
+
+	// ConfigMap name in scenario namespace.
+	// Data keys with .proto suffix are parsed as proto sources,
+	// binary data keys with .protoset suffix as compiled descriptor sets
+	ConfigMap string `json:"config_map"`
+}
+
+// Stream describes client-streaming, server-streaming or bidi call
+type Stream struct {
+	// Messages sent one by one in JSON representation
+	// When empty action body is sent as single message
+	Messages []runtime.RawExtension `json:"messages"`
+
+	// Limit stop receiving after N response messages, 0 means unlimited
+	Limit int `json:"limit"`
+
+	// Duration of receiving window for server stream, for example 10s
+	Duration metav1.Duration `json:"duration"`
 }
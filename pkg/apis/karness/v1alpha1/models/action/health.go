@@ -0,0 +1,25 @@
+package action
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// Health action waits until grpc.health.v1.Health reports SERVING status of service.
+// Result body contains last observed status and error if any.
+type Health struct {
+	// required: true
+	Addr string `json:"addr"`
+
+	// Service name registered in health server, empty checks server overall
+	Service string `json:"service"`
+
+	// Watch uses Health/Watch stream instead of polling Health/Check
+	Watch bool `json:"watch"`
+
+	// Timeout of waiting, 30s by default, result code is DeadlineExceeded when it's exceeded
+	Timeout metav1.Duration `json:"timeout"`
+
+	// Interval between checks, 1s by default
+	Interval metav1.Duration `json:"interval"`
+
+	// TLS turns on secure connection, plaintext is used by default
+	TLS *TLS `json:"tls"`
+}
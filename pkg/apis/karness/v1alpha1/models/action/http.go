@@ -1,6 +1,16 @@
 package action
 
 type HTTP struct {
-	Addr   string `json:"addr"`
+	// Addr full url of requested resource including scheme, host and path
+	// required: true
+	Addr string `json:"addr"`
+
+	// Method GET by default
 	Method string `json:"method"`
+
+	// Header contains request headers
+	Header map[string]string `json:"header"`
+
+	// Query contains url query params which are appended to Addr
+	Query map[string]string `json:"query"`
 }
@@ -0,0 +1,23 @@
+package action
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Job action runs kubernetes Job and waits until it finishes.
+// Container exit code is action result code, container logs are result body
+type Job struct {
+	// Template of job pod, restartPolicy is Never by default
+	// required: true
+	Template corev1.PodTemplateSpec `json:"template"`
+
+	// Container which exit code and logs are action result, first container by default
+	Container string `json:"container"`
+
+	// Timeout of job including pod scheduling, e.g. 5m
+	Timeout metav1.Duration `json:"timeout"`
+
+	// Keep finished job, job is deleted by default
+	Keep bool `json:"keep"`
+}
@@ -0,0 +1,21 @@
+package action
+
+// Kube action performs operation over kubernetes object, resulting object is action result body
+type Kube struct {
+	// Verb one of create, apply, patch, get, delete
+	// required: true
+	Verb string `json:"verb"`
+
+	// Manifest YAML or JSON object.
+	// Scenario namespace is used when metadata.namespace is empty, other namespaces
+	// are rejected unless controller runs with -kube-any-namespace.
+	// get, patch and delete require only apiVersion, kind and metadata
+	// required: true
+	Manifest string `json:"manifest"`
+
+	// Patch body of patch verb
+	Patch string `json:"patch"`
+
+	// PatchType of patch verb: merge (default), json or strategic
+	PatchType string `json:"patch_type"`
+}
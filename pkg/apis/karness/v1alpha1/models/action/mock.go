@@ -0,0 +1,17 @@
+package action
+
+// Mock action returns calls recorded by MockService as JSON array of
+// {"method": ..., "header": {...}, "request": {...}, "code": ..., "time": ...}
+type Mock struct {
+	// Service name of MockService in scenario namespace
+	// required: true
+	Service string `json:"service"`
+
+	// Method filters calls, all calls are returned by default
+	Method string `json:"method"`
+
+	// Match selects calls which request fields have expected values
+	// Key: json path
+	// Val: expected value
+	Match map[string]string `json:"match"`
+}
@@ -0,0 +1,13 @@
+package action
+
+import "k8s.io/apimachinery/pkg/runtime"
+
+// Plugin action is performed by out-of-process executor plugin
+type Plugin struct {
+	// Name of plugin loaded by controller with -plugin flag
+	// required: true
+	Name string `json:"name"`
+
+	// Config is opaque for karness and passed to plugin as is
+	Config runtime.RawExtension `json:"config"`
+}
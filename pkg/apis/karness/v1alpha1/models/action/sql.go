@@ -0,0 +1,24 @@
+package action
+
+// SQL action runs query or statement, result body is JSON array of at most 1000 rows
+type SQL struct {
+	// Driver postgres or mysql
+	// required: true
+	Driver string `json:"driver"`
+
+	// Secret name in scenario namespace which contains connection string
+	// required: true
+	Secret string `json:"secret"`
+
+	// Key of connection string in secret, dsn by default
+	Key string `json:"key"`
+
+	// required: true
+	Query string `json:"query"`
+
+	// Args of query placeholders
+	Args []string `json:"args"`
+
+	// Exec runs statement which doesn't return rows, result body is {"rows_affected": N}
+	Exec bool `json:"exec"`
+}
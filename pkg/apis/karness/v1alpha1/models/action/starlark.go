@@ -0,0 +1,9 @@
+package action
+
+// Starlark action runs script which is able to read and write scenario variables.
+// Script sets code, body and header globals which become result of action.
+type Starlark struct {
+	// Script source, action body is available as input global
+	// required: true
+	Script string `json:"script"`
+}
@@ -0,0 +1,33 @@
+package action
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// WebSocket action sends messages and collects received ones into result body as JSON array.
+// Collecting lasts for Duration or until message matches Match or Limit messages are received.
+type WebSocket struct {
+	// Addr ws or wss url
+	// required: true
+	Addr string `json:"addr"`
+
+	// Header contains handshake request headers
+	Header map[string]string `json:"header"`
+
+	// Messages are sent in order after connection, action body is sent when empty.
+	// String message is sent as its text, other messages as JSON
+	Messages []runtime.RawExtension `json:"messages"`
+
+	// Duration of collecting, 10s by default
+	Duration metav1.Duration `json:"duration"`
+
+	// Limit of collected messages
+	Limit int `json:"limit"`
+
+	// Match stops collecting on message which fields have expected values,
+	// result code is DeadlineExceeded when no message matched
+	// Key: json path
+	// Val: expected value
+	Match map[string]string `json:"match"`
+}
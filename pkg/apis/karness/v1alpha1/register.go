@@ -37,6 +37,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		SchemeGroupVersion,
 		&Scenario{},
 		&ScenarioList{},
+		&MockService{},
+		&MockServiceList{},
 	)
 
 	scheme.AddKnownTypes(
@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1/models/action"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type State string
@@ -42,6 +43,26 @@ type ScenarioSpec struct {
 type ScenarioStatus struct {
 	Progress string `json:"progress"`
 	State    State  `json:"state"`
+	// Message explains failure of scenario
+	Message string `json:"message,omitempty"`
+	// Mismatch explains the last unmet complete condition, it's cleared when event completes
+	Mismatch *Mismatch `json:"mismatch,omitempty"`
+}
+
+// Mismatch explains why result of event action doesn't meet complete condition
+type Mismatch struct {
+	Event string `json:"event"`
+	// Condition index in complete conditions of event
+	Condition int `json:"condition"`
+	// Path of result body field, empty when whole result is checked
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+
+	// Diff is unified diff of expected and actual body
+	Diff string `json:"diff,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -60,15 +81,51 @@ type Event struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 
+	// Poll re-invokes action until complete conditions pass.
+	// Without poll failed action is retried each second and unmet conditions fail scenario immediately.
+	Poll *Poll `json:"poll,omitempty"`
+
 	Action   Action     `json:"action"`
 	Complete Completion `json:"complete"`
 }
 
+// Poll limits attempts of event, at least one of timeout and attempts should be set
+type Poll struct {
+	// Interval between attempts, 1s by default
+	Interval metav1.Duration `json:"interval"`
+	// Timeout of event since first attempt
+	Timeout metav1.Duration `json:"timeout"`
+	// Attempts max count
+	Attempts int `json:"attempts"`
+}
+
 type Action struct {
 	Name string `json:"name"`
 
 	GRPC *action.GRPC `json:"grpc"`
 	HTTP *action.HTTP `json:"http"`
+	// Plugin action is performed by plugin registered in controller
+	Plugin *action.Plugin `json:"plugin"`
+	// Kube action operates kubernetes objects
+	Kube *action.Kube `json:"kube"`
+	// Job action runs container and captures its exit code and logs
+	Job *action.Job `json:"job"`
+	// SQL action queries database
+	SQL *action.SQL `json:"sql"`
+	// Broker action publishes or consumes kafka and nats messages
+	Broker *action.Broker `json:"broker"`
+	// Starlark action runs script with access to scenario variables
+	Starlark *action.Starlark `json:"starlark"`
+	// GraphQL action posts operation to graphql endpoint
+	GraphQL *action.GraphQL `json:"graphql"`
+	// WebSocket action exchanges messages over websocket
+	WebSocket *action.WebSocket `json:"websocket"`
+	// Mock action returns calls recorded by mock service
+	Mock *action.Mock `json:"mock"`
+	// Callback action waits for request to callback endpoint
+	Callback *action.Callback `json:"callback"`
+	// Health action waits until grpc service reports SERVING status
+	Health *action.Health `json:"health"`
 
 	Body Body `json:"body"`
 
@@ -97,20 +154,81 @@ type Completion struct {
 type Condition struct {
 	// Response of condition check
 	Response *ConditionResponse `json:"response"`
+
+	// Schema validates JSON result body
+	Schema *ConditionSchema `json:"schema"`
+
+	// CEL expression which should be true, it sees result code, body parsed as JSON,
+	// headers, action latency and scenario variables, e.g.
+	// body.items.size() > 0 && body.items[0].price < vars.limit
+	// Numbers of body and variables are double, ints should be converted: double(body.items.size()) == body.total
+	CEL string `json:"cel"`
+}
+
+// ConditionSchema is JSON Schema of result body, only one of Inline and ConfigMap should be set
+type ConditionSchema struct {
+	// Inline schema document
+	Inline runtime.RawExtension `json:"inline"`
+
+	// ConfigMap name in scenario namespace which contains schema document
+	ConfigMap string `json:"config_map"`
+
+	// Key of config map data, schema.json by default
+	Key string `json:"key"`
 }
 
 // ConditionResponse contains competition condition for source
 type ConditionResponse struct {
 	Status string `json:"status"`
 	Body   Body   `json:"body"`
+
+	// KV checks fields of JSON result body along with Body
+	KV *KV `json:"kv"`
+
+	// Subset is expected part of JSON result body along with Body.
+	// Objects of result may have extra fields, arrays should have the same length
+	// and their items are compared by position, numbers are compared by value
+	Subset runtime.RawExtension `json:"subset"`
 }
 
 type KV struct {
+	// Field matchers should be satisfied all
 	Field []KVFieldMatch `json:"field_match"`
 }
 
-// KVFields mean that key sh
+// Operator of field match
+type Operator string
+
+const (
+	// OpEq field equals value, default operator
+	OpEq Operator = "eq"
+	// OpNe field doesn't equal value
+	OpNe Operator = "ne"
+	// OpGt field is number greater than value
+	OpGt Operator = "gt"
+	// OpLt field is number less than value
+	OpLt Operator = "lt"
+	// OpRegex field matches regular expression of value
+	OpRegex Operator = "regex"
+	// OpContains string field contains substring, array has element, object has key
+	OpContains Operator = "contains"
+	// OpExists field is present, value is ignored
+	OpExists Operator = "exists"
+	// OpNotExists field is absent, value is ignored
+	OpNotExists Operator = "notExists"
+	// OpLength string, array or object field has length of value
+	OpLength Operator = "length"
+)
+
+// KVFieldMatch checks field of result body found by json path Key with Op against Value.
+// Strings are compared as is, other fields by their JSON representation.
+// When json path finds several fields every one should satisfy operator
 type KVFieldMatch struct {
-	Key   string `json:"key"`
-	Value Any    `json:"value"`
+	// Key json path, e.g. {.items[0].id}
+	Key string `json:"key"`
+
+	// Op eq by default
+	Op Operator `json:"op"`
+
+	Value Any `json:"value"`
 }
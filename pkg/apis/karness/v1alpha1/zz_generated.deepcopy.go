@@ -31,13 +31,68 @@ func (in *Action) DeepCopyInto(out *Action) {
 	if in.GRPC != nil {
 		in, out := &in.GRPC, &out.GRPC
 		*out = new(action.GRPC)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.HTTP != nil {
 		in, out := &in.HTTP, &out.HTTP
 		*out = new(action.HTTP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(action.Plugin)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kube != nil {
+		in, out := &in.Kube, &out.Kube
+		*out = new(action.Kube)
+		**out = **in
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(action.Job)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SQL != nil {
+		in, out := &in.SQL, &out.SQL
+		*out = new(action.SQL)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Broker != nil {
+		in, out := &in.Broker, &out.Broker
+		*out = new(action.Broker)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Starlark != nil {
+		in, out := &in.Starlark, &out.Starlark
+		*out = new(action.Starlark)
 		**out = **in
 	}
+	if in.GraphQL != nil {
+		in, out := &in.GraphQL, &out.GraphQL
+		*out = new(action.GraphQL)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebSocket != nil {
+		in, out := &in.WebSocket, &out.WebSocket
+		*out = new(action.WebSocket)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mock != nil {
+		in, out := &in.Mock, &out.Mock
+		*out = new(action.Mock)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Callback != nil {
+		in, out := &in.Callback, &out.Callback
+		*out = new(action.Callback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(action.Health)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Body.DeepCopyInto(&out.Body)
 	if in.BindResult != nil {
 		in, out := &in.BindResult, &out.BindResult
@@ -123,6 +178,11 @@ func (in *Condition) DeepCopyInto(out *Condition) {
 		*out = new(ConditionResponse)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(ConditionSchema)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -140,6 +200,12 @@ func (in *Condition) DeepCopy() *Condition {
 func (in *ConditionResponse) DeepCopyInto(out *ConditionResponse) {
 	*out = *in
 	in.Body.DeepCopyInto(&out.Body)
+	if in.KV != nil {
+		in, out := &in.KV, &out.KV
+		*out = new(KV)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Subset.DeepCopyInto(&out.Subset)
 	return
 }
 
@@ -153,9 +219,31 @@ func (in *ConditionResponse) DeepCopy() *ConditionResponse {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionSchema) DeepCopyInto(out *ConditionSchema) {
+	*out = *in
+	in.Inline.DeepCopyInto(&out.Inline)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionSchema.
+func (in *ConditionSchema) DeepCopy() *ConditionSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Event) DeepCopyInto(out *Event) {
 	*out = *in
+	if in.Poll != nil {
+		in, out := &in.Poll, &out.Poll
+		*out = new(Poll)
+		**out = **in
+	}
 	in.Action.DeepCopyInto(&out.Action)
 	in.Complete.DeepCopyInto(&out.Complete)
 	return
@@ -209,7 +297,105 @@ func (in *KVFieldMatch) DeepCopy() *KVFieldMatch {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Scenario) DeepCopyInto(out *Scenario) {
+func (in *Mismatch) DeepCopyInto(out *Mismatch) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mismatch.
+func (in *Mismatch) DeepCopy() *Mismatch {
+	if in == nil {
+		return nil
+	}
+	out := new(Mismatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MockGRPC) DeepCopyInto(out *MockGRPC) {
+	*out = *in
+	in.Descriptors.DeepCopyInto(&out.Descriptors)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MockGRPC.
+func (in *MockGRPC) DeepCopy() *MockGRPC {
+	if in == nil {
+		return nil
+	}
+	out := new(MockGRPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MockHTTP) DeepCopyInto(out *MockHTTP) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MockHTTP.
+func (in *MockHTTP) DeepCopy() *MockHTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(MockHTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MockResponse) DeepCopyInto(out *MockResponse) {
+	*out = *in
+	in.Body.DeepCopyInto(&out.Body)
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Delay = in.Delay
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MockResponse.
+func (in *MockResponse) DeepCopy() *MockResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(MockResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MockRule) DeepCopyInto(out *MockRule) {
+	*out = *in
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Response.DeepCopyInto(&out.Response)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MockRule.
+func (in *MockRule) DeepCopy() *MockRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MockRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MockService) DeepCopyInto(out *MockService) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -218,6 +404,134 @@ func (in *Scenario) DeepCopyInto(out *Scenario) {
 	return
 }
 
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MockService.
+func (in *MockService) DeepCopy() *MockService {
+	if in == nil {
+		return nil
+	}
+	out := new(MockService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MockService) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MockServiceList) DeepCopyInto(out *MockServiceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MockService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MockServiceList.
+func (in *MockServiceList) DeepCopy() *MockServiceList {
+	if in == nil {
+		return nil
+	}
+	out := new(MockServiceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MockServiceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MockServiceSpec) DeepCopyInto(out *MockServiceSpec) {
+	*out = *in
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(MockGRPC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(MockHTTP)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]MockRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MockServiceSpec.
+func (in *MockServiceSpec) DeepCopy() *MockServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MockServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MockServiceStatus) DeepCopyInto(out *MockServiceStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MockServiceStatus.
+func (in *MockServiceStatus) DeepCopy() *MockServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MockServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Poll) DeepCopyInto(out *Poll) {
+	*out = *in
+	out.Interval = in.Interval
+	out.Timeout = in.Timeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Poll.
+func (in *Poll) DeepCopy() *Poll {
+	if in == nil {
+		return nil
+	}
+	out := new(Poll)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Scenario) DeepCopyInto(out *Scenario) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scenario.
 func (in *Scenario) DeepCopy() *Scenario {
 	if in == nil {
@@ -302,6 +616,11 @@ func (in *ScenarioSpec) DeepCopy() *ScenarioSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScenarioStatus) DeepCopyInto(out *ScenarioStatus) {
 	*out = *in
+	if in.Mismatch != nil {
+		in, out := &in.Mismatch, &out.Mismatch
+		*out = new(Mismatch)
+		**out = **in
+	}
 	return
 }
 
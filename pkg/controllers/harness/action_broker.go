@@ -0,0 +1,190 @@
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/avroexec"
+	"github.com/d7561985/karness/pkg/executor/brokerexec"
+	"github.com/d7561985/karness/pkg/executor/grpcexec"
+	"k8s.io/klog/v2"
+)
+
+const (
+	brokerOK = "OK"
+	// brokerTimeout is result code when expected message didn't arrive
+	brokerTimeout = "DeadlineExceeded"
+
+	defaultConsumeTimeout = 30 * time.Second
+)
+
+func init() {
+	executor.Register("broker", NewBroker)
+}
+
+type brokerAction struct {
+	v1alpha1.Action
+	env executor.Env
+}
+
+func NewBroker(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	return &brokerAction{Action: in, env: env}, nil
+}
+
+func (b *brokerAction) Validate(_ context.Context) error {
+	if (b.Broker.Publish == nil) == (b.Broker.Consume == nil) {
+		return errors.New("one of broker publish and consume should be set")
+	}
+
+	if c := b.Broker.Consume; c != nil && c.Decode != nil && (c.Decode.Protobuf == "") == (c.Decode.Avro == nil) {
+		return errors.New("one of decode protobuf and avro should be set")
+	}
+
+	return brokerexec.Validate(b.Broker.Kind)
+}
+
+// Prepare subscribes consume action before scenario starts,
+// so it receives messages published in response to earlier events.
+// Broker which isn't available yet is subscribed by call.
+func (b *brokerAction) Prepare(ctx context.Context) error {
+	if b.Broker.Consume == nil {
+		return nil
+	}
+
+	if _, err := b.subscribe(ctx); err != nil {
+		klog.Warningf("broker action %q isn't subscribed before start: %v", b.Name, err)
+	}
+
+	return nil
+}
+
+func (b *brokerAction) Call(ctx context.Context) (*executor.Result, error) {
+	if b.Broker.Consume != nil {
+		timeout := b.Broker.Consume.Timeout.Duration
+		if timeout <= 0 {
+			timeout = defaultConsumeTimeout
+		}
+
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return b.consume(ctx)
+	}
+
+	broker, err := brokerexec.Open(ctx, b.Broker.Kind, b.Broker.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	defer broker.Close()
+
+	return b.publish(ctx, broker)
+}
+
+// subscribe returns subscription of scenario opened by the first call.
+// Consume actions with the same name and topic share subscription
+func (b *brokerAction) subscribe(ctx context.Context) (brokerexec.Subscription, error) {
+	name := strings.Join([]string{b.Name, b.Broker.Kind, b.Broker.Addr, b.Broker.Topic}, " ")
+
+	return brokersOf(b.env).Subscribe(ctx, b.env.Key, name, b.Broker.Kind, b.Broker.Addr, brokerexec.ConsumeRequest{
+		Topic:         b.Broker.Topic,
+		FromBeginning: b.Broker.Consume.FromBeginning,
+	})
+}
+
+func (b *brokerAction) publish(ctx context.Context, broker brokerexec.Broker) (*executor.Result, error) {
+	body, err := bodyBytes(b.Body)
+	if err != nil {
+		return nil, fmt.Errorf("broker body error: %w", err)
+	}
+
+	m := brokerexec.Message{Topic: b.Broker.Topic, Key: []byte(b.Broker.Publish.Key), Value: body}
+
+	if len(b.Broker.Publish.Header) > 0 {
+		m.Header = make(map[string][]string, len(b.Broker.Publish.Header))
+
+		for k, v := range b.Broker.Publish.Header {
+			m.Header[k] = []string{v}
+		}
+	}
+
+	if err = broker.Publish(ctx, m); err != nil {
+		return nil, err
+	}
+
+	return &executor.Result{Code: brokerOK, Body: body}, nil
+}
+
+func (b *brokerAction) consume(ctx context.Context) (*executor.Result, error) {
+	decode, err := b.decoder(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := b.subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := sub.Next(ctx, brokerexec.ConsumeRequest{
+		Topic:         b.Broker.Topic,
+		Decode:        decode,
+		Filter:        b.filter(),
+		FromBeginning: b.Broker.Consume.FromBeginning,
+	})
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &executor.Result{Code: brokerTimeout}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return &executor.Result{Code: brokerOK, Body: m.Value, Header: m.Header}, nil
+}
+
+// decoder converts protobuf or avro messages to JSON
+func (b *brokerAction) decoder(ctx context.Context) (brokerexec.Decoder, error) {
+	d := b.Broker.Consume.Decode
+
+	switch {
+	case d == nil:
+		return nil, nil
+	case d.Avro != nil:
+		decoders := decodersOf(b.env)
+		if decoders == nil {
+			decoders = avroexec.NewDecoders()
+		}
+
+		dec := decoders.Get(d.Avro.Registry, d.Avro.Subject)
+
+		return func(data []byte) ([]byte, error) {
+			return dec.Decode(ctx, data)
+		}, nil
+	default:
+		opt, err := sourceOptions(ctx, b.env, d.Descriptors)
+		if err != nil {
+			return nil, err
+		}
+
+		return grpcexec.New(opt...).Decoder(d.Protobuf)
+	}
+}
+
+// filter accepts messages which fields have expected values
+func (b *brokerAction) filter() brokerexec.Filter {
+	if len(b.Broker.Consume.Filter) == 0 {
+		return nil
+	}
+
+	return func(m brokerexec.Message) bool {
+		return fieldsMatch(m.Value, b.Broker.Consume.Filter)
+	}
+}
@@ -0,0 +1,91 @@
+package harness
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/callbackexec"
+)
+
+const (
+	callbackOK = "OK"
+	// callbackTimeout is result code when expected request didn't arrive
+	callbackTimeout = "DeadlineExceeded"
+
+	defaultCallbackTimeout = 30 * time.Second
+)
+
+var ErrNoCallbackListener = errors.New("callback listener isn't configured")
+
+func init() {
+	executor.Register("callback", NewCallback)
+}
+
+type callbackAction struct {
+	v1alpha1.Action
+	env executor.Env
+}
+
+func NewCallback(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	return &callbackAction{Action: in, env: env}, nil
+}
+
+func (c *callbackAction) Validate(_ context.Context) error {
+	if callbacksOf(c.env) == nil {
+		return ErrNoCallbackListener
+	}
+
+	if c.Callback.Variable == "" {
+		return errors.New("callback variable is required")
+	}
+
+	return nil
+}
+
+// Prepare registers endpoint and stores its url in scenario variable
+func (c *callbackAction) Prepare(_ context.Context) error {
+	e := callbacksOf(c.env).Register(c.env.Key, c.Callback.Variable)
+	c.env.Vars.Store(c.Callback.Variable, e.URL())
+
+	return nil
+}
+
+func (c *callbackAction) Call(ctx context.Context) (*executor.Result, error) {
+	callbacks := callbacksOf(c.env)
+	if callbacks == nil {
+		return nil, ErrNoCallbackListener
+	}
+
+	e := callbacks.Register(c.env.Key, c.Callback.Variable)
+
+	timeout := c.Callback.Timeout.Duration
+	if timeout <= 0 {
+		timeout = defaultCallbackTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	r, err := e.Wait(ctx, c.match)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &executor.Result{Code: callbackTimeout}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return &executor.Result{Code: callbackOK, Body: r.Body, Header: r.Header}, nil
+}
+
+func (c *callbackAction) match(r callbackexec.Request) bool {
+	if c.Callback.Method != "" && !strings.EqualFold(c.Callback.Method, r.Method) {
+		return false
+	}
+
+	return fieldsMatch(r.Body, c.Callback.Match)
+}
@@ -0,0 +1,62 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/graphqlexec"
+)
+
+func init() {
+	executor.Register("graphql", NewGraphQL)
+}
+
+type graphqlAction struct {
+	v1alpha1.Action
+	env executor.Env
+}
+
+func NewGraphQL(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	return &graphqlAction{Action: in, env: env}, nil
+}
+
+func (g *graphqlAction) Call(ctx context.Context) (*executor.Result, error) {
+	vars, err := g.variables()
+	if err != nil {
+		return nil, fmt.Errorf("graphql variables: %w", err)
+	}
+
+	code, header, body, err := graphqlexec.New().Call(ctx, graphqlexec.Request{
+		Addr:          g.GraphQL.Addr,
+		Header:        g.GraphQL.Header,
+		Query:         g.GraphQL.Query,
+		OperationName: g.GraphQL.OperationName,
+		Variables:     vars,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &executor.Result{Code: code, Body: body, Header: header}, nil
+}
+
+// variables decodes operation variables and renders their templates with scenario variables
+func (g *graphqlAction) variables() (map[string]interface{}, error) {
+	if len(g.GraphQL.Variables.Raw) == 0 {
+		return nil, nil
+	}
+
+	var vars map[string]interface{}
+	if err := json.Unmarshal(g.GraphQL.Variables.Raw, &vars); err != nil {
+		return nil, err
+	}
+
+	if _, err := renderValues(vars, g.env.Vars); err != nil {
+		return nil, err
+	}
+
+	return vars, nil
+}
@@ -2,30 +2,228 @@ package harness
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1/models/action"
+	"github.com/d7561985/karness/pkg/executor"
 	"github.com/d7561985/karness/pkg/executor/grpcexec"
+	"google.golang.org/grpc/codes"
+	corev1 "k8s.io/api/core/v1"
 )
 
+func init() {
+	executor.Register("grpc", NewGRPC)
+}
+
 type grpcAction struct {
 	v1alpha1.Action
+	env executor.Env
 }
 
-func NewGRPC(in v1alpha1.Action) Action {
-	return &grpcAction{Action: in}
+func NewGRPC(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	return &grpcAction{Action: in, env: env}, nil
 }
 
-func (g *grpcAction) Call(ctx context.Context) (*ActionResult, error) {
-	gc := grpcexec.New()
+func (g *grpcAction) Call(ctx context.Context) (*executor.Result, error) {
+	opt, err := g.options(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gc := grpcexec.New(opt...)
 
-	code, body, err := gc.Call(ctx, g.GRPC.Addr, grpcexec.Path{
+	path := grpcexec.Path{
 		Package: g.GRPC.Package,
 		Service: g.GRPC.Service,
 		RPC:     g.GRPC.RPC,
-	}, "")
+	}
+
+	var (
+		code codes.Code
+		body []byte
+	)
+
+	switch {
+	case g.GRPC.Stream != nil:
+		var req []string
+
+		if req, err = g.streamRequests(); err != nil {
+			return nil, fmt.Errorf("grpc stream messages error: %w", err)
+		}
+
+		code, body, err = gc.Stream(ctx, g.GRPC.Addr, path, req, grpcexec.StreamLimit{
+			Messages: g.GRPC.Stream.Limit,
+			Duration: g.GRPC.Stream.Duration.Duration,
+		})
+	case isBinary(g.Body):
+		code, body, err = gc.CallBinary(ctx, g.GRPC.Addr, path, g.Body.Byte)
+	default:
+		var req []byte
+
+		if req, err = bodyBytes(g.Body); err != nil {
+			return nil, fmt.Errorf("grpc body error: %w", err)
+		}
+
+		code, body, err = gc.Call(ctx, g.GRPC.Addr, path, string(req))
+	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &ActionResult{Code: code.String(), Body: body}, nil
+	return &executor.Result{Code: code.String(), Body: body}, nil
+}
+
+func (g *grpcAction) options(ctx context.Context) ([]grpcexec.Option, error) {
+	var opt []grpcexec.Option
+
+	if conns := connsOf(g.env); conns != nil {
+		opt = append(opt, grpcexec.WithManager(conns, g.env.Key))
+	}
+
+	if g.GRPC.TLS != nil {
+		tlsOpt, err := tlsOptions(ctx, g.env, g.GRPC.TLS)
+		if err != nil {
+			return nil, err
+		}
+
+		opt = append(opt, tlsOpt...)
+	}
+
+	descOpt, err := sourceOptions(ctx, g.env, g.GRPC.Descriptors)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(opt, descOpt...), nil
+}
+
+// sourceOptions returns descriptor source of action parsed once per scenario
+func sourceOptions(ctx context.Context, env executor.Env, d *action.Descriptors) ([]grpcexec.Option, error) {
+	sources := sourcesOf(env)
+	if d == nil || sources == nil {
+		return descriptorOptions(ctx, env, d)
+	}
+
+	key, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := sources.Get(string(key), func() ([]grpcexec.Option, error) {
+		return descriptorOptions(ctx, env, d)
+	})
+
+	switch {
+	case errors.Is(err, grpcexec.ErrNoDescriptors):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return []grpcexec.Option{grpcexec.WithDescriptorSource(src)}, nil
+}
+
+// descriptorOptions converts descriptor sources of action to grpcexec options
+func descriptorOptions(ctx context.Context, env executor.Env, d *action.Descriptors) ([]grpcexec.Option, error) {
+	var opt []grpcexec.Option
+
+	if d == nil {
+		return opt, nil
+	}
+
+	if len(d.Protoset) > 0 {
+		opt = append(opt, grpcexec.WithProtoset(d.Protoset...))
+	}
+
+	if len(d.Proto) > 0 {
+		opt = append(opt, grpcexec.WithProtoFiles(d.ImportPath, d.Proto...))
+	}
+
+	if d.ConfigMap != "" {
+		cm, err := kubeOf(env).ConfigMap(ctx, env.Namespace, d.ConfigMap)
+		if err != nil {
+			return nil, fmt.Errorf("descriptors config map %q: %w", d.ConfigMap, err)
+		}
+
+		protos := make(map[string]string)
+
+		for k, v := range cm.Data {
+			if strings.HasSuffix(k, ".proto") {
+				protos[k] = v
+			}
+		}
+
+		for k, v := range cm.BinaryData {
+			if strings.HasSuffix(k, ".protoset") {
+				opt = append(opt, grpcexec.WithProtosetContent(v))
+			}
+		}
+
+		if len(protos) > 0 {
+			opt = append(opt, grpcexec.WithProtoContent(protos))
+		}
+	}
+
+	return opt, nil
+}
+
+// tlsOptions converts tls settings of action to grpcexec options
+func tlsOptions(ctx context.Context, env executor.Env, t *action.TLS) ([]grpcexec.Option, error) {
+	var opt []grpcexec.Option
+
+	switch {
+	case t.Secret != "":
+		secret, err := kubeOf(env).Secret(ctx, env.Namespace, t.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("tls secret %q: %w", t.Secret, err)
+		}
+
+		opt = append(opt, grpcexec.WithTLSContent(
+			secret.Data[corev1.ServiceAccountRootCAKey],
+			secret.Data[corev1.TLSCertKey],
+			secret.Data[corev1.TLSPrivateKeyKey],
+		))
+	default:
+		// system roots are used for verification
+		opt = append(opt, grpcexec.WithTLS("", "", ""))
+	}
+
+	// insecure has to follow tls options as they turn on verification
+	if t.Insecure {
+		opt = append(opt, grpcexec.WithInsecure())
+	}
+
+	if t.ServerName != "" {
+		opt = append(opt, grpcexec.WithServerName(t.ServerName))
+	}
+
+	if t.Authority != "" {
+		opt = append(opt, grpcexec.WithAuthority(t.Authority))
+	}
+
+	return opt, nil
+}
+
+// streamRequests returns stream messages or action body as single message
+func (g *grpcAction) streamRequests() ([]string, error) {
+	if len(g.GRPC.Stream.Messages) == 0 {
+		req, err := bodyBytes(g.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{string(req)}, nil
+	}
+
+	res := make([]string, 0, len(g.GRPC.Stream.Messages))
+	for _, msg := range g.GRPC.Stream.Messages {
+		res = append(res, string(msg.Raw))
+	}
+
+	return res, nil
 }
@@ -0,0 +1,95 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/grpcexec"
+)
+
+const (
+	healthOK = "OK"
+	// healthTimeout is result code when service didn't become SERVING
+	healthTimeout = "DeadlineExceeded"
+
+	defaultHealthTimeout = 30 * time.Second
+)
+
+func init() {
+	executor.Register("health", NewHealth)
+}
+
+type healthAction struct {
+	v1alpha1.Action
+	env executor.Env
+}
+
+func NewHealth(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	return &healthAction{Action: in, env: env}, nil
+}
+
+func (h *healthAction) Validate(_ context.Context) error {
+	if h.Health.Addr == "" {
+		return errors.New("health addr is required")
+	}
+
+	return nil
+}
+
+func (h *healthAction) Call(ctx context.Context) (*executor.Result, error) {
+	var opt []grpcexec.Option
+
+	if conns := connsOf(h.env); conns != nil {
+		opt = append(opt, grpcexec.WithManager(conns, h.env.Key))
+	}
+
+	if h.Health.TLS != nil {
+		tlsOpt, err := tlsOptions(ctx, h.env, h.Health.TLS)
+		if err != nil {
+			return nil, err
+		}
+
+		opt = append(opt, tlsOpt...)
+	}
+
+	timeout := h.Health.Timeout.Duration
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	status, err := grpcexec.New(opt...).WaitServing(ctx, h.Health.Addr, grpcexec.HealthRequest{
+		Service:  h.Health.Service,
+		Watch:    h.Health.Watch,
+		Interval: h.Health.Interval.Duration,
+	})
+
+	res := struct {
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+	}{Status: status.String()}
+
+	code := healthOK
+
+	if err != nil {
+		// scenario is stopped
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, err
+		}
+
+		code, res.Error = healthTimeout, err.Error()
+	}
+
+	body, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &executor.Result{Code: code, Body: body}, nil
+}
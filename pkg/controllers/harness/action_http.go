@@ -0,0 +1,44 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/httpexec"
+)
+
+func init() {
+	executor.Register("http", NewHTTP)
+}
+
+type httpAction struct {
+	v1alpha1.Action
+}
+
+func NewHTTP(in v1alpha1.Action, _ executor.Env) (executor.Executor, error) {
+	return &httpAction{Action: in}, nil
+}
+
+func (h *httpAction) Call(ctx context.Context) (*executor.Result, error) {
+	body, err := bodyBytes(h.Body)
+	if err != nil {
+		return nil, fmt.Errorf("http body error: %w", err)
+	}
+
+	code, header, res, err := httpexec.New().Call(ctx, httpexec.Request{
+		Addr:   h.HTTP.Addr,
+		Method: h.HTTP.Method,
+		Header: h.HTTP.Header,
+		Query:  h.HTTP.Query,
+		Body:   body,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &executor.Result{Code: strconv.Itoa(code), Body: res, Header: header}, nil
+}
@@ -0,0 +1,46 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/jobexec"
+)
+
+func init() {
+	executor.Register("job", NewJob)
+}
+
+type jobAction struct {
+	v1alpha1.Action
+	env executor.Env
+}
+
+func NewJob(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	return &jobAction{Action: in, env: env}, nil
+}
+
+func (j *jobAction) Call(ctx context.Context) (*executor.Result, error) {
+	// jobs are named after scenario
+	name := j.env.Key[strings.LastIndex(j.env.Key, "/")+1:]
+
+	code, logs, err := jobexec.New(kubeOf(j.env).Clientset(),
+		jobexec.WithOwner(j.env.Key),
+		jobexec.WithKeep(j.Job.Keep),
+	).Run(ctx, jobexec.Request{
+		Namespace: j.env.Namespace,
+		Name:      name,
+		Template:  j.Job.Template,
+		Container: j.Job.Container,
+		Timeout:   j.Job.Timeout.Duration,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("job action: %w", err)
+	}
+
+	return &executor.Result{Code: strconv.Itoa(int(code)), Body: logs}, nil
+}
@@ -0,0 +1,74 @@
+package harness
+
+import (
+	"context"
+	"errors"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/kubeexec"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// kubeOK is result code of succeeded kube action,
+// failed API calls return status reason, e.g. NotFound or AlreadyExists
+const kubeOK = "OK"
+
+func init() {
+	executor.Register("kube", NewKube)
+}
+
+type kubeAction struct {
+	v1alpha1.Action
+	env executor.Env
+}
+
+func NewKube(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	return &kubeAction{Action: in, env: env}, nil
+}
+
+func (k *kubeAction) Validate(_ context.Context) error {
+	return kubeexec.Validate(k.request())
+}
+
+func (k *kubeAction) Call(ctx context.Context) (*executor.Result, error) {
+	obj, err := kubeexec.New(kubeOf(k.env).Resource, kubeOptionsOf(k.env)...).Do(ctx, k.request())
+
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		// API refused request: let conditions check status reason
+		body, err := json.Marshal(status.Status())
+		if err != nil {
+			return nil, err
+		}
+
+		code := string(status.Status().Reason)
+		if code == "" {
+			code = status.Status().Status
+		}
+
+		return &executor.Result{Code: code, Body: body}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return &executor.Result{Code: kubeOK, Body: body}, nil
+}
+
+func (k *kubeAction) request() kubeexec.Request {
+	return kubeexec.Request{
+		Verb:      kubeexec.Verb(k.Kube.Verb),
+		Namespace: k.env.Namespace,
+		Manifest:  []byte(k.Kube.Manifest),
+		Patch:     []byte(k.Kube.Patch),
+		PatchType: k.Kube.PatchType,
+	}
+}
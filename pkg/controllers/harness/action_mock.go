@@ -0,0 +1,52 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/mockexec"
+)
+
+// mockOK is result code of mock action
+const mockOK = "OK"
+
+var ErrMockNotRunning = errors.New("mock service isn't running")
+
+func init() {
+	executor.Register("mock", NewMock)
+}
+
+type mockAction struct {
+	v1alpha1.Action
+	env executor.Env
+}
+
+func NewMock(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	return &mockAction{Action: in, env: env}, nil
+}
+
+func (m *mockAction) Call(_ context.Context) (*executor.Result, error) {
+	srv, ok := mocksOf(m.env).Get(m.env.Namespace + "/" + m.Mock.Service)
+	if !ok {
+		return nil, fmt.Errorf("%q: %w", m.Mock.Service, ErrMockNotRunning)
+	}
+
+	calls := make([]mockexec.Call, 0)
+
+	for _, c := range srv.Calls(m.Mock.Method) {
+		if fieldsMatch(c.Request, m.Mock.Match) {
+			calls = append(calls, c)
+		}
+	}
+
+	body, err := json.Marshal(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &executor.Result{Code: mockOK, Body: body}, nil
+}
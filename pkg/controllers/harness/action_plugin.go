@@ -0,0 +1,72 @@
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/pluginexec"
+	"github.com/d7561985/karness/pkg/executor/pluginexec/pb"
+)
+
+var ErrPluginNotLoaded = errors.New("plugin is not loaded")
+
+func init() {
+	executor.Register("plugin", NewPlugin)
+}
+
+type pluginAction struct {
+	v1alpha1.Action
+	env    executor.Env
+	client *pluginexec.Client
+}
+
+func NewPlugin(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	c, ok := pluginexec.Get(in.Plugin.Name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrPluginNotLoaded, in.Plugin.Name)
+	}
+
+	return &pluginAction{Action: in, env: env, client: c}, nil
+}
+
+// Validate asks plugin to check its config
+func (p *pluginAction) Validate(ctx context.Context) error {
+	res, err := p.client.Validate(ctx, &pb.ValidateRequest{Config: p.Plugin.Config.Raw})
+	if err != nil {
+		return fmt.Errorf("plugin %q validate: %w", p.Plugin.Name, err)
+	}
+
+	if len(res.Errors) > 0 {
+		return fmt.Errorf("plugin %q config: %s", p.Plugin.Name, strings.Join(res.Errors, "; "))
+	}
+
+	return nil
+}
+
+func (p *pluginAction) Call(ctx context.Context) (*executor.Result, error) {
+	body, err := bodyBytes(p.Body)
+	if err != nil {
+		return nil, fmt.Errorf("plugin body error: %w", err)
+	}
+
+	res, err := p.client.Execute(ctx, &pb.ExecuteRequest{
+		Config:    p.Plugin.Config.Raw,
+		Body:      body,
+		Namespace: p.env.Namespace,
+		Scenario:  p.env.Key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("plugin %q execute: %w", p.Plugin.Name, err)
+	}
+
+	header := make(map[string][]string, len(res.Headers))
+	for k, v := range res.Headers {
+		header[k] = v.GetValues()
+	}
+
+	return &executor.Result{Code: res.Code, Body: res.Body, Header: header}, nil
+}
@@ -0,0 +1,82 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/sqlexec"
+)
+
+const (
+	// sqlOK is result code of succeeded sql action
+	sqlOK = "OK"
+
+	defaultSQLKey = "dsn"
+
+	// sqlMaxRows keeps result body of unbounded query in memory limits
+	sqlMaxRows = 1000
+)
+
+func init() {
+	executor.Register("sql", NewSQL)
+}
+
+type sqlAction struct {
+	v1alpha1.Action
+	env executor.Env
+}
+
+func NewSQL(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	return &sqlAction{Action: in, env: env}, nil
+}
+
+func (s *sqlAction) Validate(_ context.Context) error {
+	return sqlexec.Validate(s.SQL.Driver)
+}
+
+func (s *sqlAction) Call(ctx context.Context) (*executor.Result, error) {
+	dsn, err := s.dsn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, len(s.SQL.Args))
+	for i, a := range s.SQL.Args {
+		args[i] = a
+	}
+
+	body, err := sqlexec.New(sqlexec.WithMaxRows(sqlMaxRows)).Call(ctx, sqlexec.Request{
+		Driver: s.SQL.Driver,
+		DSN:    dsn,
+		Query:  s.SQL.Query,
+		Args:   args,
+		Exec:   s.SQL.Exec,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &executor.Result{Code: sqlOK, Body: body}, nil
+}
+
+// dsn reads connection string from secret
+func (s *sqlAction) dsn(ctx context.Context) (string, error) {
+	secret, err := kubeOf(s.env).Secret(ctx, s.env.Namespace, s.SQL.Secret)
+	if err != nil {
+		return "", fmt.Errorf("sql secret %q: %w", s.SQL.Secret, err)
+	}
+
+	key := s.SQL.Key
+	if key == "" {
+		key = defaultSQLKey
+	}
+
+	dsn, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("sql secret %q doesn't contain %q", s.SQL.Secret, key)
+	}
+
+	return string(dsn), nil
+}
@@ -0,0 +1,46 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/starlarkexec"
+)
+
+func init() {
+	executor.Register("starlark", NewStarlark)
+}
+
+type starlarkAction struct {
+	v1alpha1.Action
+	env executor.Env
+}
+
+func NewStarlark(in v1alpha1.Action, env executor.Env) (executor.Executor, error) {
+	return &starlarkAction{Action: in, env: env}, nil
+}
+
+func (s *starlarkAction) Validate(_ context.Context) error {
+	return starlarkexec.Compile(s.Name, s.Starlark.Script)
+}
+
+func (s *starlarkAction) Call(ctx context.Context) (*executor.Result, error) {
+	input, err := bodyBytes(s.Body)
+	if err != nil {
+		return nil, fmt.Errorf("starlark body error: %w", err)
+	}
+
+	res, err := starlarkexec.New().Run(ctx, starlarkexec.Request{
+		Name:   s.Name,
+		Script: s.Starlark.Script,
+		Input:  input,
+		Vars:   s.env.Vars,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &executor.Result{Code: res.Code, Body: res.Body, Header: res.Header}, nil
+}
@@ -0,0 +1,98 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/wsexec"
+)
+
+const (
+	wsOK = "OK"
+	// wsTimeout is result code when no received message matched
+	wsTimeout = "DeadlineExceeded"
+
+	defaultWebSocketDuration = 10 * time.Second
+)
+
+func init() {
+	executor.Register("websocket", NewWebSocket)
+}
+
+type webSocketAction struct {
+	v1alpha1.Action
+}
+
+func NewWebSocket(in v1alpha1.Action, _ executor.Env) (executor.Executor, error) {
+	return &webSocketAction{Action: in}, nil
+}
+
+func (w *webSocketAction) Call(ctx context.Context) (*executor.Result, error) {
+	msgs, err := w.messages()
+	if err != nil {
+		return nil, fmt.Errorf("websocket messages error: %w", err)
+	}
+
+	duration := w.WebSocket.Duration.Duration
+	if duration <= 0 {
+		duration = defaultWebSocketDuration
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	req := wsexec.Request{
+		Addr:     w.WebSocket.Addr,
+		Header:   w.WebSocket.Header,
+		Messages: msgs,
+		Limit:    w.WebSocket.Limit,
+	}
+
+	if len(w.WebSocket.Match) > 0 {
+		req.Match = func(msg []byte) bool {
+			return fieldsMatch(msg, w.WebSocket.Match)
+		}
+	}
+
+	body, header, matched, err := wsexec.New().Exchange(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	code := wsOK
+	if req.Match != nil && !matched {
+		code = wsTimeout
+	}
+
+	return &executor.Result{Code: code, Body: body, Header: header}, nil
+}
+
+// messages returns scripted messages or action body as single message,
+// JSON string is sent as its text without quotes
+func (w *webSocketAction) messages() ([][]byte, error) {
+	if len(w.WebSocket.Messages) == 0 {
+		body, err := bodyBytes(w.Body)
+		if err != nil || body == nil {
+			return nil, err
+		}
+
+		return [][]byte{body}, nil
+	}
+
+	res := make([][]byte, 0, len(w.WebSocket.Messages))
+	for _, msg := range w.WebSocket.Messages {
+		var text string
+		if err := json.Unmarshal(msg.Raw, &text); err == nil {
+			res = append(res, []byte(text))
+			continue
+		}
+
+		res = append(res, msg.Raw)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,40 @@
+package harness
+
+import (
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/executor"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// bodyBytes returns raw representation of body
+// Only one of body fields supposed to be set, priority: JSON, Byte, KV
+func bodyBytes(b v1alpha1.Body) ([]byte, error) {
+	switch {
+	case b.JSON != nil:
+		return []byte(*b.JSON), nil
+	case len(b.Byte) > 0:
+		return b.Byte, nil
+	case len(b.KV) > 0:
+		return json.Marshal(b.KV)
+	default:
+		return nil, nil
+	}
+}
+
+// isBinary reports whether body should be treated as encoded message rather than JSON
+func isBinary(b v1alpha1.Body) bool {
+	return b.JSON == nil && len(b.Byte) > 0
+}
+
+// fieldsMatch reports whether JSON body has expected values at json paths
+func fieldsMatch(body []byte, fields map[string]string) bool {
+	res := &executor.Result{Body: body}
+
+	for jpath, expect := range fields {
+		if val, err := res.GetKeyValue(jpath); err != nil || val != expect {
+			return false
+		}
+	}
+
+	return true
+}
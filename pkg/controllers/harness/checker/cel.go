@@ -0,0 +1,116 @@
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types"
+)
+
+var ErrNotBool = errors.New("expression result isn't bool")
+
+// Input of CEL expression
+type Input struct {
+	Code string
+	// Body is parsed as JSON, raw string is used when body isn't JSON
+	Body []byte
+	// Header values are joined by comma
+	Header  map[string][]string
+	Latency time.Duration
+	// Vars are scenario variables, JSON numbers and booleans are converted to double and bool
+	Vars map[string]string
+}
+
+// Expression is compiled CEL condition.
+// Declared variables: code string, body dyn, headers map(string, string), latency duration, vars map(string, dyn).
+// Numbers of body and vars are double as in CEL JSON mapping, so ints should be converted: double(body.items.size())
+type Expression struct {
+	prg cel.Program
+}
+
+func NewExpression(expr string) (*Expression, error) {
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewVar("code", decls.String),
+		decls.NewVar("body", decls.Dyn),
+		decls.NewVar("headers", decls.NewMapType(decls.String, decls.String)),
+		decls.NewVar("latency", decls.Duration),
+		decls.NewVar("vars", decls.NewMapType(decls.String, decls.Dyn)),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("cel: %w", iss.Err())
+	}
+
+	// dyn result, e.g. field of body, is checked by evaluation
+	if t := ast.ResultType(); !proto.Equal(t, decls.Bool) && !proto.Equal(t, decls.Dyn) {
+		return nil, fmt.Errorf("cel: %w: %s", ErrNotBool, t)
+	}
+
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("cel: %w", err)
+	}
+
+	return &Expression{prg: prg}, nil
+}
+
+// Is evaluates expression, errors of evaluation such as missing fields are returned
+func (e *Expression) Is(in Input) (bool, error) {
+	var body interface{}
+	if err := json.Unmarshal(in.Body, &body); err != nil {
+		body = string(in.Body)
+	}
+
+	headers := make(map[string]string, len(in.Header))
+	for k, v := range in.Header {
+		headers[k] = strings.Join(v, ",")
+	}
+
+	vars := make(map[string]interface{}, len(in.Vars))
+	for k, v := range in.Vars {
+		vars[k] = scalar(v)
+	}
+
+	out, _, err := e.prg.Eval(map[string]interface{}{
+		"code":    in.Code,
+		"body":    body,
+		"headers": headers,
+		"latency": in.Latency,
+		"vars":    vars,
+	})
+	if err != nil {
+		return false, fmt.Errorf("cel: %w", err)
+	}
+
+	res, ok := out.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("cel: %w: %s", ErrNotBool, out.Type().TypeName())
+	}
+
+	return bool(res), nil
+}
+
+// scalar converts string which is JSON number or boolean as a whole, e.g. "007" and "12abc" stay strings
+func scalar(s string) interface{} {
+	var res interface{}
+	if err := json.Unmarshal([]byte(s), &res); err != nil {
+		return s
+	}
+
+	switch res.(type) {
+	case float64, bool:
+		return res
+	default:
+		return s
+	}
+}
@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpression(t *testing.T) {
+	in := Input{
+		Code:    "200",
+		Body:    []byte(`{"id":"a1b2","items":[{"sku":"x","price":9.5},{"sku":"y","price":120}],"total":2}`),
+		Header:  map[string][]string{"Content-Type": {"application/json"}, "X-Trace": {"a", "b"}},
+		Latency: 150 * time.Millisecond,
+		Vars:    map[string]string{"limit": "100", "ID": "a1b2", "strict": "true", "zip": "007", "build": "12abc"},
+	}
+
+	tests := []struct {
+		name   string
+		expr   string
+		expect bool
+		err    bool
+	}{
+		{"code", `code == "200"`, true, false},
+		{"cross field", `body.items.size() > 0 && body.items[0].price < vars.limit`, true, false},
+		{"cross field mismatch", `body.items.all(i, i.price < vars.limit)`, false, false},
+		{"count", `double(body.items.size()) == body.total`, true, false},
+		{"count int", `body.items.size() == body.total`, false, true},
+		{"var string", `body.id == vars.ID`, true, false},
+		{"var bool", `vars.strict`, true, false},
+		{"var keeps leading zero", `vars.zip == "007"`, true, false},
+		{"var keeps trailing text", `vars.build == "12abc"`, true, false},
+		{"var number", `vars.limit == 100.0`, true, false},
+		{"headers", `headers["Content-Type"].startsWith("application/json") && headers["X-Trace"] == "a,b"`, true, false},
+		{"latency", `latency < duration("200ms")`, true, false},
+		{"latency mismatch", `latency < duration("100ms")`, false, false},
+		{"missing field", `body.error.code == 1`, false, true},
+		{"not bool", `body.id`, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := NewExpression(test.expr)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			ok, err := e.Is(in)
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expect, ok)
+		})
+	}
+}
+
+func TestExpressionCompile(t *testing.T) {
+	for _, expr := range []string{
+		`body.items.size( > 0`,
+		`unknown == 1`,
+		`code + 1`,
+		`latency`,
+	} {
+		_, err := NewExpression(expr)
+		assert.Error(t, err, expr)
+	}
+
+	// plain text body is string
+	e, err := NewExpression(`body.contains("pong")`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ok, err := e.Is(Input{Body: []byte("pong")})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
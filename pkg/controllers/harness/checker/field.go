@@ -0,0 +1,221 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+var (
+	ErrUnknownOperator = errors.New("unknown operator")
+	ErrBadJsonPath     = errors.New("bad json path formula")
+)
+
+// FieldCheck checks field of decoded JSON body
+type FieldCheck v1alpha1.KVFieldMatch
+
+// Validate reports errors of json path, operator and value before check
+func (f FieldCheck) Validate() error {
+	if !strings.HasPrefix(strings.TrimSpace(f.Key), "{") {
+		return fmt.Errorf("%q: %w", f.Key, ErrBadJsonPath)
+	}
+
+	if err := jsonpath.New(f.Key).Parse(f.Key); err != nil {
+		return fmt.Errorf("%q: %w", f.Key, err)
+	}
+
+	var err error
+
+	switch f.Op {
+	case "", v1alpha1.OpEq, v1alpha1.OpNe, v1alpha1.OpContains, v1alpha1.OpExists, v1alpha1.OpNotExists:
+	case v1alpha1.OpGt, v1alpha1.OpLt:
+		_, err = strconv.ParseFloat(string(f.Value), 64)
+	case v1alpha1.OpLength:
+		_, err = strconv.Atoi(string(f.Value))
+	case v1alpha1.OpRegex:
+		_, err = regexp.Compile(string(f.Value))
+	default:
+		return fmt.Errorf("%q: %w", f.Op, ErrUnknownOperator)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s value of %q: %w", f.Op, f.Key, err)
+	}
+
+	return nil
+}
+
+// Is reports whether fields found in doc satisfy operator
+func (f FieldCheck) Is(doc interface{}) (bool, error) {
+	m, err := f.Check(doc)
+
+	return m == nil && err == nil, err
+}
+
+// Check returns explanation when fields found in doc don't satisfy operator
+func (f FieldCheck) Check(doc interface{}) (*Mismatch, error) {
+	j := jsonpath.New(f.Key).AllowMissingKeys(true)
+	if err := j.Parse(f.Key); err != nil {
+		return nil, fmt.Errorf("%q: %w", f.Key, err)
+	}
+
+	res, err := j.FindResults(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", f.Key, err)
+	}
+
+	var values []interface{}
+
+	for _, r := range res {
+		for _, v := range r {
+			if v.IsValid() && v.CanInterface() {
+				values = append(values, v.Interface())
+			}
+		}
+	}
+
+	switch {
+	case f.Op == v1alpha1.OpNotExists && len(values) > 0:
+		return &Mismatch{Path: f.Key, Message: "field exists", Actual: join(values)}, nil
+	case f.Op == v1alpha1.OpNotExists:
+		return nil, nil
+	case len(values) == 0:
+		return &Mismatch{Path: f.Key, Message: "field is missing"}, nil
+	case f.Op == v1alpha1.OpExists:
+		return nil, nil
+	}
+
+	for _, v := range values {
+		ok, err := f.match(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			op := f.Op
+			if op == "" {
+				op = v1alpha1.OpEq
+			}
+
+			return &Mismatch{Path: f.Key, Message: fmt.Sprintf("%s mismatch", op), Expected: string(f.Value), Actual: join(values)}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (f FieldCheck) match(v interface{}) (bool, error) {
+	expect := string(f.Value)
+
+	switch f.Op {
+	case "", v1alpha1.OpEq:
+		return str(v) == expect, nil
+	case v1alpha1.OpNe:
+		return str(v) != expect, nil
+	case v1alpha1.OpGt, v1alpha1.OpLt:
+		n, ok := number(v)
+		if !ok {
+			return false, nil
+		}
+
+		e, err := strconv.ParseFloat(expect, 64)
+		if err != nil {
+			return false, err
+		}
+
+		if f.Op == v1alpha1.OpGt {
+			return n > e, nil
+		}
+
+		return n < e, nil
+	case v1alpha1.OpRegex:
+		return regexp.MatchString(expect, str(v))
+	case v1alpha1.OpContains:
+		return contains(v, expect), nil
+	case v1alpha1.OpLength:
+		e, err := strconv.Atoi(expect)
+		if err != nil {
+			return false, err
+		}
+
+		switch rv := reflect.ValueOf(v); rv.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map:
+			return rv.Len() == e, nil
+		default:
+			return false, nil
+		}
+	default:
+		return false, fmt.Errorf("%q: %w", f.Op, ErrUnknownOperator)
+	}
+}
+
+// str returns string as is and JSON representation of other values
+func str(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+// join string representation of values
+func join(values []interface{}) string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		res = append(res, str(v))
+	}
+
+	return strings.Join(res, ", ")
+}
+
+// number of JSON value or numeric string
+func number(v interface{}) (float64, bool) {
+	if s, ok := v.(string); ok {
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	}
+
+	return jsonNumber(v)
+}
+
+// jsonNumber is decoded as int64 or float64
+func jsonNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+func contains(v interface{}, expect string) bool {
+	switch t := v.(type) {
+	case string:
+		return strings.Contains(t, expect)
+	case []interface{}:
+		for _, item := range t {
+			if str(item) == expect {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		_, ok := t[expect]
+		return ok
+	}
+
+	return false
+}
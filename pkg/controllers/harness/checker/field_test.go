@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+func TestFieldCheck(t *testing.T) {
+	body := `{"id":"a1b2","created":"2021-03-06T13:21:28Z","total":42.5,"count":3,"paid":true,
+"tags":["new","vip"],"items":[{"sku":"x","qty":1},{"sku":"y","qty":2}],"meta":{"source":"web"}}`
+
+	var doc interface{}
+	if !assert.NoError(t, json.Unmarshal([]byte(body), &doc)) {
+		return
+	}
+
+	tests := []struct {
+		name   string
+		field  v1alpha1.KVFieldMatch
+		expect bool
+	}{
+		{"eq default", v1alpha1.KVFieldMatch{Key: "{.id}", Value: "a1b2"}, true},
+		{"eq number", v1alpha1.KVFieldMatch{Key: "{.count}", Op: v1alpha1.OpEq, Value: "3"}, true},
+		{"eq bool", v1alpha1.KVFieldMatch{Key: "{.paid}", Op: v1alpha1.OpEq, Value: "true"}, true},
+		{"eq mismatch", v1alpha1.KVFieldMatch{Key: "{.id}", Op: v1alpha1.OpEq, Value: "zzz"}, false},
+		{"ne", v1alpha1.KVFieldMatch{Key: "{.id}", Op: v1alpha1.OpNe, Value: "zzz"}, true},
+		{"gt", v1alpha1.KVFieldMatch{Key: "{.total}", Op: v1alpha1.OpGt, Value: "40"}, true},
+		{"gt equal", v1alpha1.KVFieldMatch{Key: "{.count}", Op: v1alpha1.OpGt, Value: "3"}, false},
+		{"gt not number", v1alpha1.KVFieldMatch{Key: "{.meta}", Op: v1alpha1.OpGt, Value: "0"}, false},
+		{"lt", v1alpha1.KVFieldMatch{Key: "{.count}", Op: v1alpha1.OpLt, Value: "10"}, true},
+		{"lt every item", v1alpha1.KVFieldMatch{Key: "{.items[*].qty}", Op: v1alpha1.OpLt, Value: "2"}, false},
+		{"regex", v1alpha1.KVFieldMatch{Key: "{.created}", Op: v1alpha1.OpRegex, Value: `^\d{4}-\d{2}-\d{2}T`}, true},
+		{"regex mismatch", v1alpha1.KVFieldMatch{Key: "{.id}", Op: v1alpha1.OpRegex, Value: `^\d+$`}, false},
+		{"contains string", v1alpha1.KVFieldMatch{Key: "{.created}", Op: v1alpha1.OpContains, Value: "2021"}, true},
+		{"contains array", v1alpha1.KVFieldMatch{Key: "{.tags}", Op: v1alpha1.OpContains, Value: "vip"}, true},
+		{"contains array mismatch", v1alpha1.KVFieldMatch{Key: "{.tags}", Op: v1alpha1.OpContains, Value: "old"}, false},
+		{"contains object key", v1alpha1.KVFieldMatch{Key: "{.meta}", Op: v1alpha1.OpContains, Value: "source"}, true},
+		{"exists", v1alpha1.KVFieldMatch{Key: "{.meta.source}", Op: v1alpha1.OpExists}, true},
+		{"exists missing", v1alpha1.KVFieldMatch{Key: "{.error}", Op: v1alpha1.OpExists}, false},
+		{"notExists", v1alpha1.KVFieldMatch{Key: "{.error}", Op: v1alpha1.OpNotExists}, true},
+		{"notExists present", v1alpha1.KVFieldMatch{Key: "{.id}", Op: v1alpha1.OpNotExists}, false},
+		{"length array", v1alpha1.KVFieldMatch{Key: "{.items}", Op: v1alpha1.OpLength, Value: "2"}, true},
+		{"length string", v1alpha1.KVFieldMatch{Key: "{.id}", Op: v1alpha1.OpLength, Value: "4"}, true},
+		{"length mismatch", v1alpha1.KVFieldMatch{Key: "{.tags}", Op: v1alpha1.OpLength, Value: "3"}, false},
+		{"missing field", v1alpha1.KVFieldMatch{Key: "{.error.code}", Op: v1alpha1.OpNe, Value: "1"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := FieldCheck(test.field)
+			if !assert.NoError(t, f.Validate()) {
+				return
+			}
+
+			ok, err := f.Is(doc)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, ok)
+		})
+	}
+}
+
+func TestFieldCheckValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		field v1alpha1.KVFieldMatch
+	}{
+		{"no braces", v1alpha1.KVFieldMatch{Key: ".id", Value: "1"}},
+		{"bad path", v1alpha1.KVFieldMatch{Key: "{.items[}", Value: "1"}},
+		{"unknown operator", v1alpha1.KVFieldMatch{Key: "{.id}", Op: "like", Value: "1"}},
+		{"gt not number", v1alpha1.KVFieldMatch{Key: "{.id}", Op: v1alpha1.OpGt, Value: "one"}},
+		{"length not number", v1alpha1.KVFieldMatch{Key: "{.id}", Op: v1alpha1.OpLength, Value: "one"}},
+		{"bad regex", v1alpha1.KVFieldMatch{Key: "{.id}", Op: v1alpha1.OpRegex, Value: "("}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Error(t, FieldCheck(test.field).Validate())
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// maxDiff limits size of diff stored in scenario status
+const maxDiff = 4096
+
+// Mismatch explains why result doesn't meet condition
+type Mismatch struct {
+	// Path of result body field, empty when whole result is checked
+	Path    string
+	Message string
+
+	Expected string
+	Actual   string
+
+	// Diff is unified diff of expected and actual body
+	Diff string
+}
+
+func (m *Mismatch) Error() string {
+	var b strings.Builder
+
+	if m.Path != "" {
+		b.WriteString(m.Path + ": ")
+	}
+
+	b.WriteString(m.Message)
+
+	if m.Expected != "" || m.Actual != "" {
+		fmt.Fprintf(&b, ": expected %s, got %s", m.Expected, m.Actual)
+	}
+
+	return b.String()
+}
+
+// diff returns unified diff of documents
+func diff(expect, actual []byte) string {
+	d, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        lines(expect),
+		B:        lines(actual),
+		FromFile: "expected",
+		ToFile:   "actual",
+		Context:  2,
+	})
+	if err != nil {
+		return ""
+	}
+
+	if len(d) > maxDiff {
+		d = d[:maxDiff] + "\n..."
+	}
+
+	return d
+}
+
+// lines of document, JSON is formatted with sorted keys and indent
+func lines(doc []byte) []string {
+	text := string(doc)
+
+	var v interface{}
+	if err := json.Unmarshal(doc, &v); err == nil {
+		var b bytes.Buffer
+
+		enc := json.NewEncoder(&b)
+		enc.SetIndent("", "  ")
+
+		if err = enc.Encode(v); err == nil {
+			text = b.String()
+		}
+	}
+
+	return difflib.SplitLines(strings.TrimSuffix(text, "\n"))
+}
@@ -7,36 +7,118 @@ import (
 
 	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
 	"k8s.io/apimachinery/pkg/util/json"
-	"k8s.io/klog/v2"
 )
 
 type ResCheck v1alpha1.ConditionResponse
 
-func (r ResCheck) Is(status string, res []byte) bool {
-	if r.Status != "" {
-		if r.Status != status {
-			fmt.Println(">>1")
-			return false
+// Validate reports errors of field matchers
+func (r ResCheck) Validate() error {
+	if r.KV == nil {
+		return nil
+	}
+
+	for i, field := range r.KV.Field {
+		if err := FieldCheck(field).Validate(); err != nil {
+			return fmt.Errorf("field match %d: %w", i, err)
 		}
 	}
 
-	if r.Body.JSON != nil {
-		return *r.Body.JSON == string(res)
+	return nil
+}
+
+func (r ResCheck) Is(status string, res []byte) bool {
+	return r.Check(status, res) == nil
+}
+
+// Check returns explanation of the first unmet part of condition, nil when result meets it
+func (r ResCheck) Check(status string, res []byte) *Mismatch {
+	if r.Status != "" && r.Status != status {
+		return &Mismatch{Message: "status mismatch", Expected: r.Status, Actual: status}
+	}
+
+	if m := r.body(res); m != nil {
+		return m
 	}
 
-	if len(r.Body.Byte) > 0 {
-		return bytes.Equal(r.Body.Byte, res)
+	if m := r.subset(res); m != nil {
+		return m
 	}
 
-	if len(r.Body.KV) > 0 {
+	return r.fields(res)
+}
+
+func (r ResCheck) body(res []byte) *Mismatch {
+	var expect []byte
+
+	switch {
+	case r.Body.JSON != nil:
+		if *r.Body.JSON == string(res) {
+			return nil
+		}
+
+		expect = []byte(*r.Body.JSON)
+	case len(r.Body.Byte) > 0:
+		if bytes.Equal(r.Body.Byte, res) {
+			return nil
+		}
+
+		expect = r.Body.Byte
+	case len(r.Body.KV) > 0:
 		m := make(map[string]v1alpha1.Any)
 		if err := json.Unmarshal(res, &m); err != nil {
-			klog.Errorf("result (%s) cant' unmarshal to map", string(res))
-			return false
+			return &Mismatch{Message: fmt.Sprintf("body isn't flat JSON object: %v", err)}
+		}
+
+		if reflect.DeepEqual(r.Body.KV, m) {
+			return nil
 		}
 
-		return reflect.DeepEqual(r.Body.KV, m)
+		expect, _ = json.Marshal(r.Body.KV)
+	default:
+		return nil
+	}
+
+	return &Mismatch{Message: "body mismatch", Diff: diff(expect, res)}
+}
+
+func (r ResCheck) subset(res []byte) *Mismatch {
+	if len(r.Subset.Raw) == 0 {
+		return nil
+	}
+
+	err := Subset(r.Subset.Raw, res)
+	if err == nil {
+		return nil
+	}
+
+	// extra fields of result aren't mismatch, so path of the first difference is reported instead of diff
+	if m, ok := err.(*Mismatch); ok {
+		return m
+	}
+
+	return &Mismatch{Message: err.Error()}
+}
+
+func (r ResCheck) fields(res []byte) *Mismatch {
+	if r.KV == nil || len(r.KV.Field) == 0 {
+		return nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(res, &doc); err != nil {
+		return &Mismatch{Message: fmt.Sprintf("body isn't JSON: %v", err)}
+	}
+
+	for _, field := range r.KV.Field {
+		m, err := FieldCheck(field).Check(doc)
+		if err != nil {
+			return &Mismatch{Path: field.Key, Message: err.Error()}
+		}
+
+		if m != nil {
+			return m
+		}
 	}
 
-	return true
+	return nil
 }
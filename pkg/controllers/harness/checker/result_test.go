@@ -0,0 +1,97 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestResCheck(t *testing.T) {
+	body := `{"id":7,"status":"done","items":[{"sku":"x"}]}`
+	flat := `{"id":"7","status":"done"}`
+	expect := `{"id":7,"status":"new","items":[{"sku":"x"}]}`
+
+	tests := []struct {
+		name     string
+		check    v1alpha1.ConditionResponse
+		mismatch *Mismatch
+		err      string
+	}{
+		{"match", v1alpha1.ConditionResponse{Status: "OK", Body: v1alpha1.Body{JSON: &body}}, nil, ""},
+		{
+			"status",
+			v1alpha1.ConditionResponse{Status: "NotFound"},
+			&Mismatch{Message: "status mismatch", Expected: "NotFound", Actual: "OK"},
+			"status mismatch: expected NotFound, got OK",
+		},
+		{
+			"json body",
+			v1alpha1.ConditionResponse{Body: v1alpha1.Body{JSON: &expect}},
+			&Mismatch{Message: "body mismatch", Diff: `--- expected
++++ actual
+@@ -6,4 +6,4 @@
+     }
+   ],
+-  "status": "new"
++  "status": "done"
+ }
+`},
+			"body mismatch",
+		},
+		{
+			"kv body",
+			v1alpha1.ConditionResponse{Body: v1alpha1.Body{KV: map[string]v1alpha1.Any{"id": "7", "status": "new"}}},
+			&Mismatch{Message: "body mismatch", Diff: `--- expected
++++ actual
+@@ -1,4 +1,4 @@
+ {
+   "id": "7",
+-  "status": "new"
++  "status": "done"
+ }
+`},
+			"body mismatch",
+		},
+		{
+			"subset",
+			v1alpha1.ConditionResponse{Subset: runtime.RawExtension{Raw: []byte(`{"items":[{"sku":"y"}]}`)}},
+			&Mismatch{Path: "$.items[0].sku", Message: "subset mismatch", Expected: `"y"`, Actual: `"x"`},
+			`$.items[0].sku: subset mismatch: expected "y", got "x"`,
+		},
+		{
+			"field",
+			v1alpha1.ConditionResponse{KV: &v1alpha1.KV{Field: []v1alpha1.KVFieldMatch{
+				{Key: "{.id}", Op: v1alpha1.OpExists},
+				{Key: "{.id}", Op: v1alpha1.OpGt, Value: "10"},
+			}}},
+			&Mismatch{Path: "{.id}", Message: "gt mismatch", Expected: "10", Actual: "7"},
+			"{.id}: gt mismatch: expected 10, got 7",
+		},
+		{
+			"missing field",
+			v1alpha1.ConditionResponse{KV: &v1alpha1.KV{Field: []v1alpha1.KVFieldMatch{{Key: "{.error}", Value: "1"}}}},
+			&Mismatch{Path: "{.error}", Message: "field is missing"},
+			"{.error}: field is missing",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := body
+			if len(test.check.Body.KV) > 0 {
+				res = flat
+			}
+
+			m := ResCheck(test.check).Check("OK", []byte(res))
+			if test.mismatch == nil {
+				assert.Nil(t, m)
+				return
+			}
+
+			assert.Equal(t, test.mismatch, m)
+			assert.EqualError(t, m, test.err)
+		})
+	}
+}
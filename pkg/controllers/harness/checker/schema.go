@@ -0,0 +1,45 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Schema is compiled JSON Schema
+type Schema struct {
+	s *gojsonschema.Schema
+}
+
+func NewSchema(doc []byte) (*Schema, error) {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(doc))
+	if err != nil {
+		return nil, fmt.Errorf("json schema: %w", err)
+	}
+
+	return &Schema{s: s}, nil
+}
+
+// Validate returns error which lists all violations of schema by JSON body in sorted order
+func (s *Schema) Validate(body []byte) error {
+	res, err := s.s.Validate(gojsonschema.NewBytesLoader(body))
+	if err != nil {
+		return fmt.Errorf("result body: %w", err)
+	}
+
+	if res.Valid() {
+		return nil
+	}
+
+	errs := make([]string, 0, len(res.Errors()))
+	for _, e := range res.Errors() {
+		errs = append(errs, e.String())
+	}
+
+	// order of errors isn't stable, sorted list doesn't change status between attempts
+	sort.Strings(errs)
+
+	return fmt.Errorf("schema: %s", strings.Join(errs, "; "))
+}
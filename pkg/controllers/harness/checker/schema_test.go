@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	s, err := NewSchema([]byte(`{
+  "type": "object",
+  "required": ["id", "user"],
+  "properties": {
+    "id": {"type": "integer"},
+    "user": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {"name": {"type": "string"}, "age": {"type": "integer", "minimum": 0}}
+    }
+  }
+}`))
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{"valid", `{"id":1,"user":{"name":"alice","age":30},"extra":true}`, ""},
+		{"missing", `{"user":{"name":"alice"}}`, "schema: (root): id is required"},
+		{"all errors", `{"id":"1","user":{"age":-1}}`,
+			"schema: id: Invalid type. Expected: integer, given: string; user.age: Must be greater than or equal to 0; user: name is required"},
+		{"not json", `not json`, "result body: invalid character 'o' in literal null (expecting 'u')"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := s.Validate([]byte(test.body))
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, test.err)
+		})
+	}
+
+	_, err = NewSchema([]byte(`{"type": 1}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// Subset reports the first field of expected JSON document in order of keys which isn't found in actual document.
+// Objects of actual document may have extra fields, arrays should have the same length
+// and their items are compared by position, numbers are compared by value
+func Subset(expect, actual []byte) error {
+	var e, a interface{}
+
+	if err := json.Unmarshal(expect, &e); err != nil {
+		return fmt.Errorf("expected document: %w", err)
+	}
+
+	if err := json.Unmarshal(actual, &a); err != nil {
+		return fmt.Errorf("result body: %w", err)
+	}
+
+	if m := subset("$", e, a); m != nil {
+		return m
+	}
+
+	return nil
+}
+
+func subset(path string, expect, actual interface{}) *Mismatch {
+	switch e := expect.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return mismatch(path, expect, actual)
+		}
+
+		// map order isn't stable, sorted keys report the same first mismatch between attempts
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			av, ok := a[k]
+			if !ok {
+				return &Mismatch{Path: path + "." + k, Message: "field is missing"}
+			}
+
+			if m := subset(path+"."+k, e[k], av); m != nil {
+				return m
+			}
+		}
+
+		return nil
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			return mismatch(path, expect, actual)
+		}
+
+		if len(e) != len(a) {
+			return &Mismatch{
+				Path:     path,
+				Message:  "length mismatch",
+				Expected: fmt.Sprint(len(e)),
+				Actual:   fmt.Sprint(len(a)),
+			}
+		}
+
+		for i := range e {
+			if m := subset(fmt.Sprintf("%s[%d]", path, i), e[i], a[i]); m != nil {
+				return m
+			}
+		}
+
+		return nil
+	}
+
+	if en, ok := jsonNumber(expect); ok {
+		if an, ok := jsonNumber(actual); ok && en == an {
+			return nil
+		}
+
+		return mismatch(path, expect, actual)
+	}
+
+	if !reflect.DeepEqual(expect, actual) {
+		return mismatch(path, expect, actual)
+	}
+
+	return nil
+}
+
+func mismatch(path string, expect, actual interface{}) *Mismatch {
+	e, _ := json.Marshal(expect)
+	a, _ := json.Marshal(actual)
+
+	return &Mismatch{Path: path, Message: "subset mismatch", Expected: string(e), Actual: string(a)}
+}
@@ -0,0 +1,48 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubset(t *testing.T) {
+	actual := `{"id":"a1b2","created":"2021-03-06T13:21:28Z","total":42.5,"count":3,"paid":true,"coupon":null,
+"user":{"name":"alice","address":{"city":"Riga","zip":"LV-1050"}},"items":[{"sku":"x","qty":1},{"sku":"y","qty":2}]}`
+
+	tests := []struct {
+		name   string
+		expect string
+		err    string
+	}{
+		{"empty object", `{}`, ""},
+		{"same document", actual, ""},
+		{"nested", `{"user":{"address":{"city":"Riga"}}}`, ""},
+		{"types", `{"total":42.5,"count":3.0,"paid":true,"coupon":null}`, ""},
+		{"array items", `{"items":[{"sku":"x"},{"qty":2}]}`, ""},
+		{"missing field", `{"user":{"email":"a@b.c"}}`, "$.user.email: field is missing"},
+		{"nested value", `{"user":{"address":{"city":"Tallinn"}}}`, `$.user.address.city: subset mismatch: expected "Tallinn", got "Riga"`},
+		{"number", `{"count":4}`, "$.count: subset mismatch: expected 4, got 3"},
+		{"number as string", `{"count":"3"}`, `$.count: subset mismatch: expected "3", got 3`},
+		{"bool", `{"paid":false}`, "$.paid: subset mismatch: expected false, got true"},
+		{"null", `{"paid":null}`, "$.paid: subset mismatch: expected null, got true"},
+		{"array length", `{"items":[{"sku":"x"}]}`, "$.items: length mismatch: expected 1, got 2"},
+		{"array item", `{"items":[{"sku":"x"},{"sku":"z"}]}`, `$.items[1].sku: subset mismatch: expected "z", got "y"`},
+		{"first of several", `{"user":{"name":"bob","address":{"city":"Tallinn"}},"paid":false,"count":4}`, "$.count: subset mismatch: expected 4, got 3"},
+		{"type", `{"user":"alice"}`, `$.user: subset mismatch: expected "alice", got {"address":{"city":"Riga","zip":"LV-1050"},"name":"alice"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Subset([]byte(test.expect), []byte(actual))
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, test.err)
+		})
+	}
+
+	assert.Error(t, Subset([]byte(`{}`), []byte(`not json`)))
+}
@@ -7,14 +7,68 @@ import (
 
 	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
 	"github.com/d7561985/karness/pkg/controllers"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/avroexec"
+	"github.com/d7561985/karness/pkg/executor/brokerexec"
+	"github.com/d7561985/karness/pkg/executor/callbackexec"
+	"github.com/d7561985/karness/pkg/executor/grpcexec"
+	"github.com/d7561985/karness/pkg/executor/kubeexec"
+	"github.com/d7561985/karness/pkg/executor/mockexec"
 )
 
+type Option func(*Config)
+
+type Config struct {
+	callbacks   *callbackexec.Listener
+	kubeOptions []kubeexec.Option
+}
+
+// WithCallbacks enables callback actions served by listener
+func WithCallbacks(l *callbackexec.Listener) Option {
+	return func(c *Config) {
+		c.callbacks = l
+	}
+}
+
+// WithKubeAnyNamespace allows kube actions to operate objects outside of scenario namespace
+func WithKubeAnyNamespace() Option {
+	return func(c *Config) {
+		c.kubeOptions = append(c.kubeOptions, kubeexec.WithAnyNamespace())
+	}
+}
+
 type Harness struct {
+	Config
+
 	// key: object
 	// value: context.CancelFunc
 	cancels sync.Map
 
 	store sync.Map
+
+	// conns shared by actions of all scenarios, scenario key is connection owner
+	conns *grpcexec.Manager
+
+	// mocks running servers of mock services
+	mocks *mockexec.Registry
+
+	// brokers subscriptions of consume actions, scenario key is subscription owner
+	brokers *brokerexec.Registry
+}
+
+func New(opt ...Option) *Harness {
+	c := Config{}
+
+	for _, o := range opt {
+		o(&c)
+	}
+
+	return &Harness{
+		Config:  c,
+		conns:   grpcexec.NewManager(0),
+		mocks:   mockexec.NewRegistry(),
+		brokers: brokerexec.NewRegistry(),
+	}
 }
 
 func (h *Harness) Factory(root context.Context, c controllers.Kube, key string, obj interface{}) error {
@@ -23,21 +77,62 @@ func (h *Harness) Factory(root context.Context, c controllers.Kube, key string,
 
 	switch item := obj.(type) {
 	case *v1alpha1.Scenario:
-		p := newScenarioProcessor(c, item)
+		services := h.services(c)
+		// schemas and descriptors are cached for scenario
+		services[serviceDecoders] = avroexec.NewDecoders()
+		services[serviceSources] = grpcexec.NewSources()
+
+		p := newScenarioProcessor(c, item, executor.Env{
+			Namespace: item.Namespace,
+			Key:       key,
+			Services:  services,
+		})
 		h.store.Store(key, p)
 
+		// ready status is reported before processor starts changing it
+		err := c.Update(item)
+
 		go p.Start(ctx)
 
-		return c.Update(item)
+		return err
+	case *v1alpha1.MockService:
+		p := newMockProcessor(c, item, executor.Env{
+			Namespace: item.Namespace,
+			Key:       key,
+			Services:  h.services(c),
+		})
+		h.store.Store(key, p)
+
+		go p.Start(ctx)
+
+		return nil
 	default:
 		panic(fmt.Errorf("upredictable object: %v[%[1]T]", item))
 	}
 }
 
+// services of built-in executors shared by all objects
+func (h *Harness) services(c controllers.Kube) executor.Services {
+	s := executor.Services{
+		controllers.ServiceKube: c,
+		serviceConns:            h.conns,
+		serviceMocks:            h.mocks,
+		serviceBrokers:          h.brokers,
+		serviceKubeOptions:      h.kubeOptions,
+	}
+
+	if h.callbacks != nil {
+		s[serviceCallbacks] = h.callbacks
+	}
+
+	return s
+}
+
 func (h *Harness) GetProcessor(key string) (interface{}, bool) {
 	return h.store.Load(key)
 }
 
+// Stop processing and close all resources owned by object
 func (h *Harness) Stop(key string) {
 	obj, ok := h.cancels.Load(key)
 	if !ok {
@@ -45,4 +140,21 @@ func (h *Harness) Stop(key string) {
 	}
 
 	obj.(context.CancelFunc)()
+	h.conns.Release(key)
+	h.brokers.Release(key)
+
+	if h.callbacks != nil {
+		h.callbacks.Release(key)
+	}
+}
+
+// Close stops all processors and closes shared resources
+func (h *Harness) Close() {
+	h.cancels.Range(func(key, value interface{}) bool {
+		value.(context.CancelFunc)()
+		return true
+	})
+
+	h.conns.Close()
+	h.brokers.Close()
 }
@@ -0,0 +1,139 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/controllers"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/grpcexec"
+	"github.com/d7561985/karness/pkg/executor/mockexec"
+	"k8s.io/klog/v2"
+)
+
+// listenRetry interval, port may be still busy by server of previous mock generation
+const listenRetry = time.Second
+
+type mockProcessor struct {
+	entity  *v1alpha1.MockService
+	control controllers.Kube
+	env     executor.Env
+}
+
+func newMockProcessor(c controllers.Kube, item *v1alpha1.MockService, env executor.Env) Processor {
+	return &mockProcessor{control: c, entity: item.DeepCopy(), env: env}
+}
+
+// Start runs servers until ctx is done
+func (m *mockProcessor) Start(ctx context.Context) {
+	srv, err := m.server(ctx)
+	if err != nil {
+		m.fail(ctx, err)
+		return
+	}
+
+	for {
+		if err = srv.Start(); err == nil {
+			break
+		}
+
+		klog.Errorf("mock service %s: %v", m.env.Key, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetry):
+		}
+	}
+
+	defer srv.Close()
+
+	name := m.entity.Namespace + "/" + m.entity.Name
+
+	mocksOf(m.env).Register(name, srv)
+	defer mocksOf(m.env).Remove(name, srv)
+
+	m.entity.Status = v1alpha1.MockServiceStatus{
+		State: v1alpha1.Serving,
+		GRPC:  srv.GRPCAddr(),
+		HTTP:  srv.HTTPAddr(),
+	}
+
+	if err = m.control.UpdateMock(m.entity); err != nil {
+		klog.Errorf("mock service %s: %v", m.env.Key, err)
+	}
+
+	<-ctx.Done()
+}
+
+func (m *mockProcessor) fail(ctx context.Context, err error) {
+	// processor was stopped, nothing to report
+	if ctx.Err() != nil {
+		return
+	}
+
+	klog.Errorf("mock service %s: %v", m.env.Key, err)
+
+	m.entity.Status = v1alpha1.MockServiceStatus{State: v1alpha1.Failed, Message: err.Error()}
+	if err = m.control.UpdateMock(m.entity); err != nil {
+		klog.Errorf("mock service %s: %v", m.env.Key, err)
+	}
+}
+
+func (m *mockProcessor) server(ctx context.Context) (*mockexec.Server, error) {
+	spec := m.entity.Spec
+
+	if spec.GRPC == nil && spec.HTTP == nil {
+		return nil, fmt.Errorf("one of grpc and http should be set")
+	}
+
+	rules := make([]mockexec.Rule, 0, len(spec.Rules))
+
+	for _, r := range spec.Rules {
+		body, err := bodyBytes(r.Response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q body: %w", r.Method, err)
+		}
+
+		rule := mockexec.Rule{
+			Method: r.Method,
+			Code:   r.Response.Code,
+			Body:   body,
+			Header: r.Response.Header,
+			Delay:  r.Response.Delay.Duration,
+		}
+
+		if len(r.Match) > 0 {
+			match := r.Match
+			rule.Match = func(req []byte) bool {
+				return fieldsMatch(req, match)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	var opt []mockexec.Option
+
+	if spec.GRPC != nil {
+		descOpt, err := descriptorOptions(ctx, m.env, &spec.GRPC.Descriptors)
+		if err != nil {
+			return nil, err
+		}
+
+		src, err := grpcexec.New(descOpt...).Descriptors()
+		if err != nil {
+			return nil, fmt.Errorf("grpc descriptors: %w", err)
+		}
+
+		opt = append(opt, mockexec.WithGRPC(fmt.Sprintf(":%d", spec.GRPC.Port), src))
+	}
+
+	if spec.HTTP != nil {
+		opt = append(opt, mockexec.WithHTTP(fmt.Sprintf(":%d", spec.HTTP.Port)))
+	}
+
+	return mockexec.New(rules, opt...), nil
+}
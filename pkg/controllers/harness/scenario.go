@@ -2,6 +2,7 @@ package harness
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -9,22 +10,63 @@ import (
 	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
 	"github.com/d7561985/karness/pkg/controllers"
 	"github.com/d7561985/karness/pkg/controllers/harness/checker"
+	"github.com/d7561985/karness/pkg/executor"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 )
 
+const (
+	// defaultInterval between attempts of event
+	defaultInterval = time.Second
+	// defaultSchemaKey of config map with schema of condition
+	defaultSchemaKey = "schema.json"
+)
+
+// ReasonConditionMismatch is reason of event recorded when action result doesn't meet complete condition
+const ReasonConditionMismatch = "ConditionMismatch"
+
+// ErrConditionMismatch is returned when action result doesn't meet complete conditions
+var ErrConditionMismatch = errors.New("complete condition doesn't match")
+
+// conditionError explains which condition isn't met and why
+type conditionError struct {
+	condition int
+	mismatch  *checker.Mismatch
+}
+
+func (e *conditionError) Error() string {
+	return fmt.Sprintf("condition %d: %s: %s", e.condition, ErrConditionMismatch, e.mismatch)
+}
+
+func (e *conditionError) Unwrap() error {
+	return ErrConditionMismatch
+}
+
 type scenarioProcessor struct {
+	// mu serializes steps made by Start and external callers
+	mu sync.Mutex
+
 	entity  *v1alpha1.Scenario
 	control controllers.Kube
+	env     executor.Env
 	store   sync.Map
 	// only complete function is possible to increment current check
 	current int
+	// attempts of current event and time of the first one
+	attempts int
+	started  time.Time
+	// schemas of conditions are compiled once: *v1alpha1.ConditionSchema: *checker.Schema
+	schemas sync.Map
+	// expressions of conditions are compiled once: string: *checker.Expression
+	expressions sync.Map
 }
 
-func newScenarioProcessor(c controllers.Kube, item *v1alpha1.Scenario) Processor {
+func newScenarioProcessor(c controllers.Kube, item *v1alpha1.Scenario, env executor.Env) Processor {
 	item.Status.Progress = sFmt(0, len(item.Spec.Events))
 	item.Status.State = v1alpha1.Ready
 
-	p := &scenarioProcessor{control: c, entity: item}
+	p := &scenarioProcessor{control: c, entity: item, env: env}
+	p.env.Vars = &p.store
 
 	for k, v := range item.Spec.Variables {
 		p.store.Store(k, v)
@@ -35,11 +77,32 @@ func newScenarioProcessor(c controllers.Kube, item *v1alpha1.Scenario) Processor
 
 // Start ...
 func (s *scenarioProcessor) Start(ctx context.Context) {
+	if err := s.prepare(ctx); err != nil {
+		// processor was stopped, nothing to report
+		if ctx.Err() != nil {
+			return
+		}
+
+		klog.Errorf("scenario validation: %v", err)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.entity.Status.State = v1alpha1.Failed
+		s.entity.Status.Message = err.Error()
+
+		if err = s.control.Update(s.entity); err != nil {
+			klog.Errorf("scenario processor: %v", err)
+		}
+
+		return
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-time.After(time.Second):
+		case <-time.After(s.interval()):
 			if s.Step(ctx) {
 				return
 			}
@@ -47,7 +110,90 @@ func (s *scenarioProcessor) Start(ctx context.Context) {
 	}
 }
 
+// prepare checks actions of all events which executors support validation
+// and registers resources of executors which require preparation
+func (s *scenarioProcessor) prepare(ctx context.Context) error {
+	for _, event := range s.entity.Spec.Events {
+		if p := event.Poll; p != nil && p.Timeout.Duration <= 0 && p.Attempts <= 0 {
+			return fmt.Errorf("event %q: poll requires timeout or attempts", event.Name)
+		}
+
+		if err := s.prepareConditions(ctx, event.Complete.Condition); err != nil {
+			return fmt.Errorf("event %q %w", event.Name, err)
+		}
+
+		kind, err := executor.Kind(event.Action)
+
+		switch {
+		case errors.Is(err, executor.ErrNoAction):
+			continue
+		case err != nil:
+			return fmt.Errorf("event %q: %w", event.Name, err)
+		}
+
+		e, err := executor.New(kind, event.Action, s.env)
+		if err != nil {
+			return fmt.Errorf("event %q: %w", event.Name, err)
+		}
+
+		if v, ok := e.(executor.Validator); ok {
+			if err = v.Validate(ctx); err != nil {
+				return fmt.Errorf("event %q %s action: %w", event.Name, kind, err)
+			}
+		}
+
+		if p, ok := e.(executor.Preparer); ok {
+			if err = p.Prepare(ctx); err != nil {
+				return fmt.Errorf("event %q %s action: %w", event.Name, kind, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// prepareConditions checks field matchers and compiles schemas and expressions
+func (s *scenarioProcessor) prepareConditions(ctx context.Context, c []v1alpha1.Condition) error {
+	for i, condition := range c {
+		if condition.Response != nil {
+			if err := checker.ResCheck(*condition.Response).Validate(); err != nil {
+				return fmt.Errorf("condition %d: %w", i, err)
+			}
+		}
+
+		if condition.Schema != nil {
+			if _, err := s.schema(ctx, condition.Schema); err != nil {
+				return fmt.Errorf("condition %d: %w", i, err)
+			}
+		}
+
+		if condition.CEL != "" {
+			if _, err := s.expression(condition.CEL); err != nil {
+				return fmt.Errorf("condition %d: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// interval between attempts of current event
+func (s *scenarioProcessor) interval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ev := s.entity.Spec.Events
+	if s.current < len(ev) && ev[s.current].Poll != nil && ev[s.current].Poll.Interval.Duration > 0 {
+		return ev[s.current].Poll.Interval.Duration
+	}
+
+	return defaultInterval
+}
+
 func (s *scenarioProcessor) Step(ctx context.Context) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.entity.Status.State = v1alpha1.InProgress
 	ev := s.entity.Spec.Events
 
@@ -55,16 +201,37 @@ func (s *scenarioProcessor) Step(ctx context.Context) bool {
 		s.entity.Status.Progress = sFmt(s.current, len(ev))
 
 		if err := s.control.Update(s.entity); err != nil {
-			klog.Errorf("scenario processor: %w", err)
+			klog.Errorf("scenario processor: %v", err)
 		}
 	}()
 
-	if !s.process(ctx, s.entity.Spec.Events[s.current]) {
-		s.entity.Status.State = v1alpha1.Failed
-		// exit on fail
+	// nothing left, e.g. steps were made by another caller
+	if len(ev) <= s.current {
+		s.entity.Status.State = v1alpha1.Complete
 		return true
 	}
 
+	event := ev[s.current]
+
+	if err := s.process(ctx, event); err != nil {
+		s.report(event, err)
+
+		if err = s.retry(event, err); err != nil {
+			klog.Errorf("scenario %s failed: %v", s.entity.Name, err)
+
+			s.entity.Status.State = v1alpha1.Failed
+			s.entity.Status.Message = err.Error()
+			// exit on fail
+			return true
+		}
+
+		return false
+	}
+
+	s.current++
+	s.attempts = 0
+	s.entity.Status.Mismatch = nil
+
 	if len(ev) <= s.current {
 		s.entity.Status.State = v1alpha1.Complete
 		return true
@@ -73,26 +240,77 @@ func (s *scenarioProcessor) Step(ctx context.Context) bool {
 	return false
 }
 
-func (s *scenarioProcessor) process(ctx context.Context, event v1alpha1.Event) bool {
+// process makes attempt of event, action call is limited by poll timeout
+func (s *scenarioProcessor) process(ctx context.Context, event v1alpha1.Event) error {
+	if s.attempts == 0 {
+		s.started = time.Now()
+	}
+
+	s.attempts++
+
+	if p := event.Poll; p != nil && p.Timeout.Duration > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithDeadline(ctx, s.started.Add(p.Timeout.Duration))
+		defer cancel()
+	}
+
 	res, err := s.action(ctx, event.Action)
 	if err != nil {
-		// ToDo: write error
-		return true
+		return err
 	}
 
-	return s.checkComplete(event.Complete.Condition, res)
+	return s.checkComplete(ctx, event.Complete.Condition, res)
 }
 
-func (s *scenarioProcessor) action(ctx context.Context, a v1alpha1.Action) (res *ActionResult, err error) {
-	res = OK()
+// retry returns nil when failed event should be attempted again, otherwise reason of failure.
+// Without poll action errors are retried until success and unmet conditions fail immediately.
+func (s *scenarioProcessor) retry(event v1alpha1.Event, err error) error {
+	p := event.Poll
 
-	if a.GRPC != nil {
-		res, err = NewGRPC(a).Call(ctx)
+	switch {
+	case p == nil && errors.Is(err, ErrConditionMismatch):
+		return fmt.Errorf("event %q: %w", event.Name, err)
+	case p == nil:
+		return nil
+	case p.Attempts > 0 && s.attempts >= p.Attempts:
+		return fmt.Errorf("event %q: %d attempts exhausted: %w", event.Name, s.attempts, err)
+	case p.Timeout.Duration > 0 && time.Since(s.started) >= p.Timeout.Duration:
+		return fmt.Errorf("event %q: timeout %s exceeded after %d attempts: %w",
+			event.Name, p.Timeout.Duration, s.attempts, err)
+	}
+
+	klog.V(2).Infof("scenario %s event %q attempt %d: %v", s.entity.Name, event.Name, s.attempts, err)
+
+	return nil
+}
+
+func (s *scenarioProcessor) action(ctx context.Context, a v1alpha1.Action) (res *executor.Result, err error) {
+	res = executor.OK()
+
+	kind, err := executor.Kind(a)
+
+	switch {
+	case errors.Is(err, executor.ErrNoAction):
+		// nothing to call, bindings and conditions work with OK result
+	case err != nil:
+		return nil, fmt.Errorf("action %q: %w", a.Name, err)
+	default:
+		e, err := executor.New(kind, a, s.env)
+		if err != nil {
+			return nil, fmt.Errorf("action %q: %w", a.Name, err)
+		}
+
+		start := time.Now()
+
+		res, err = e.Call(ctx)
 		if err != nil {
-			klog.Errorf("scenario progress with action %q grpc call error %w", a.Name, err)
-			// ok=true:  we want to try again
+			klog.Errorf("scenario progress with action %q %s call error %v", a.Name, kind, err)
+			// failed call is retried by Step
 			return nil, err
 		}
+
+		res.Latency = time.Since(start)
 	}
 
 	for variable, jpath := range a.BindResult {
@@ -107,18 +325,160 @@ func (s *scenarioProcessor) action(ctx context.Context, a v1alpha1.Action) (res
 	return res, nil
 }
 
-func (s *scenarioProcessor) checkComplete(c []v1alpha1.Condition, result *ActionResult) bool {
-	for _, condition := range c {
-		if condition.Response != nil {
-			if !checker.ResCheck(*condition.Response).Is(result.Code, result.Body) {
+func (s *scenarioProcessor) checkComplete(ctx context.Context, c []v1alpha1.Condition, result *executor.Result) error {
+	for i, condition := range c {
+		m, err := s.check(ctx, condition, result)
+		if err != nil {
+			return fmt.Errorf("condition %d: %w", i, err)
+		}
 
-				return false
-			}
+		if m != nil {
+			return &conditionError{condition: i, mismatch: m}
 		}
 	}
 
-	s.current++
-	return true
+	return nil
+}
+
+// check returns explanation of unmet condition
+func (s *scenarioProcessor) check(ctx context.Context, c v1alpha1.Condition, result *executor.Result) (*checker.Mismatch, error) {
+	if c.Response != nil {
+		if m := checker.ResCheck(*c.Response).Check(result.Code, result.Body); m != nil {
+			return m, nil
+		}
+	}
+
+	if c.Schema != nil {
+		schema, err := s.schema(ctx, c.Schema)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = schema.Validate(result.Body); err != nil {
+			return &checker.Mismatch{Message: err.Error()}, nil
+		}
+	}
+
+	if c.CEL != "" {
+		expr, err := s.expression(c.CEL)
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err := expr.Is(checker.Input{
+			Code:    result.Code,
+			Body:    result.Body,
+			Header:  result.Header,
+			Latency: result.Latency,
+			Vars:    s.vars(),
+		})
+
+		switch {
+		case err != nil:
+			return &checker.Mismatch{Message: err.Error()}, nil
+		case !ok:
+			return &checker.Mismatch{Message: "expression is false: " + c.CEL}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// report stores explanation of unmet condition in status, event is recorded when another field or condition is unmet
+func (s *scenarioProcessor) report(event v1alpha1.Event, err error) {
+	var ce *conditionError
+	if !errors.As(err, &ce) {
+		return
+	}
+
+	m := &v1alpha1.Mismatch{
+		Event:     event.Name,
+		Condition: ce.condition,
+		Path:      ce.mismatch.Path,
+		Message:   ce.mismatch.Message,
+		Expected:  ce.mismatch.Expected,
+		Actual:    ce.mismatch.Actual,
+		Diff:      ce.mismatch.Diff,
+	}
+
+	prev := s.entity.Status.Mismatch
+	s.entity.Status.Mismatch = m
+
+	// actual value may change between attempts, the same unmet field isn't recorded again
+	if prev != nil && prev.Event == m.Event && prev.Condition == m.Condition && prev.Path == m.Path {
+		return
+	}
+
+	s.control.Event(s.entity, corev1.EventTypeWarning, ReasonConditionMismatch, fmt.Sprintf("event %q %s", event.Name, ce))
+}
+
+// expression returns compiled CEL expression of condition
+func (s *scenarioProcessor) expression(cel string) (*checker.Expression, error) {
+	if v, ok := s.expressions.Load(cel); ok {
+		return v.(*checker.Expression), nil
+	}
+
+	expr, err := checker.NewExpression(cel)
+	if err != nil {
+		return nil, err
+	}
+
+	s.expressions.Store(cel, expr)
+
+	return expr, nil
+}
+
+// vars returns string representation of scenario variables
+func (s *scenarioProcessor) vars() map[string]string {
+	res := make(map[string]string)
+
+	s.store.Range(func(k, v interface{}) bool {
+		res[fmt.Sprint(k)] = fmt.Sprint(v)
+		return true
+	})
+
+	return res
+}
+
+// schema returns compiled schema of condition, config map is read only once
+func (s *scenarioProcessor) schema(ctx context.Context, c *v1alpha1.ConditionSchema) (*checker.Schema, error) {
+	if v, ok := s.schemas.Load(c); ok {
+		return v.(*checker.Schema), nil
+	}
+
+	if (len(c.Inline.Raw) == 0) == (c.ConfigMap == "") {
+		return nil, errors.New("one of schema inline and config map should be set")
+	}
+
+	doc := c.Inline.Raw
+
+	if c.ConfigMap != "" {
+		key := c.Key
+		if key == "" {
+			key = defaultSchemaKey
+		}
+
+		cm, err := kubeOf(s.env).ConfigMap(ctx, s.env.Namespace, c.ConfigMap)
+		if err != nil {
+			return nil, fmt.Errorf("schema config map %q: %w", c.ConfigMap, err)
+		}
+
+		data, ok := cm.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("schema config map %q has no key %q", c.ConfigMap, key)
+		}
+
+		doc = []byte(data)
+	}
+
+	schema, err := checker.NewSchema(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	s.schemas.Store(c, schema)
+
+	return schema, nil
 }
 
 func sFmt(start, end int) string {
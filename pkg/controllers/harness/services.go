@@ -0,0 +1,68 @@
+package harness
+
+import (
+	"github.com/d7561985/karness/pkg/controllers"
+	"github.com/d7561985/karness/pkg/executor"
+	"github.com/d7561985/karness/pkg/executor/avroexec"
+	"github.com/d7561985/karness/pkg/executor/brokerexec"
+	"github.com/d7561985/karness/pkg/executor/callbackexec"
+	"github.com/d7561985/karness/pkg/executor/grpcexec"
+	"github.com/d7561985/karness/pkg/executor/kubeexec"
+	"github.com/d7561985/karness/pkg/executor/mockexec"
+)
+
+// names of services which harness provides to built-in executors, kube is controllers.ServiceKube
+const (
+	serviceConns     = "grpc.conns"
+	serviceMocks     = "mocks"
+	serviceCallbacks = "callbacks"
+	serviceBrokers   = "brokers"
+	serviceDecoders  = "avro.decoders"
+	serviceSources   = "grpc.sources"
+	// serviceKubeOptions of kube actions configured for controller
+	serviceKubeOptions = "kube.options"
+)
+
+func kubeOf(env executor.Env) controllers.Kube {
+	k, _ := env.Service(controllers.ServiceKube).(controllers.Kube)
+	return k
+}
+
+func kubeOptionsOf(env executor.Env) []kubeexec.Option {
+	opt, _ := env.Service(serviceKubeOptions).([]kubeexec.Option)
+	return opt
+}
+
+// connsOf returns shared grpc connections, nil when connections aren't shared
+func connsOf(env executor.Env) *grpcexec.Manager {
+	m, _ := env.Service(serviceConns).(*grpcexec.Manager)
+	return m
+}
+
+func mocksOf(env executor.Env) *mockexec.Registry {
+	r, _ := env.Service(serviceMocks).(*mockexec.Registry)
+	return r
+}
+
+// callbacksOf returns callback listener, nil when it isn't configured
+func callbacksOf(env executor.Env) *callbackexec.Listener {
+	l, _ := env.Service(serviceCallbacks).(*callbackexec.Listener)
+	return l
+}
+
+func brokersOf(env executor.Env) *brokerexec.Registry {
+	r, _ := env.Service(serviceBrokers).(*brokerexec.Registry)
+	return r
+}
+
+// decodersOf returns avro decoders of scenario, nil when schemas aren't cached
+func decodersOf(env executor.Env) *avroexec.Decoders {
+	d, _ := env.Service(serviceDecoders).(*avroexec.Decoders)
+	return d
+}
+
+// sourcesOf returns grpc descriptor sources of scenario, nil when they aren't cached
+func sourcesOf(env executor.Env) *grpcexec.Sources {
+	s, _ := env.Service(serviceSources).(*grpcexec.Sources)
+	return s
+}
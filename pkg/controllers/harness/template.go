@@ -0,0 +1,60 @@
+package harness
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// render executes text template with scenario variables as data, e.g. {{ .USER_ID }}
+func render(text string, vars *sync.Map) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template %q: %w", text, err)
+	}
+
+	data := make(map[string]interface{})
+
+	if vars != nil {
+		vars.Range(func(k, v interface{}) bool {
+			data[fmt.Sprint(k)] = fmt.Sprint(v)
+			return true
+		})
+	}
+
+	var b strings.Builder
+	if err = tpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("template %q: %w", text, err)
+	}
+
+	return b.String(), nil
+}
+
+// renderValues renders string values of decoded JSON recursively
+func renderValues(v interface{}, vars *sync.Map) (interface{}, error) {
+	var err error
+
+	switch val := v.(type) {
+	case string:
+		return render(val, vars)
+	case map[string]interface{}:
+		for k, item := range val {
+			if val[k], err = renderValues(item, vars); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, item := range val {
+			if val[i], err = renderValues(item, vars); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return v, nil
+}
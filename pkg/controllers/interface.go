@@ -4,10 +4,36 @@ import (
 	"context"
 
 	api "github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 )
 
+// ServiceKube is name of executor.Env service which provides Kube
+const ServiceKube = "kube"
+
 type Kube interface {
 	Update(item *api.Scenario) error
+
+	// Event records kubernetes event of object
+	Event(object runtime.Object, eventType, reason, message string)
+
+	// UpdateMock updates status of mock service
+	UpdateMock(item *api.MockService) error
+
+	// ConfigMap returns config map which is referenced by scenario
+	ConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
+
+	// Secret returns secret which is referenced by scenario
+	Secret(ctx context.Context, namespace, name string) (*corev1.Secret, error)
+
+	// Resource returns dynamic client of resources of given kind, namespace is ignored for cluster scoped resources
+	Resource(ctx context.Context, gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error)
+
+	// Clientset of core kubernetes API
+	Clientset() kubernetes.Interface
 }
 
 type HarnessFactory interface {
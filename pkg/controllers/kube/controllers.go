@@ -3,12 +3,19 @@ package kube
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/d7561985/karness/pkg/controllers/harness"
+	"github.com/d7561985/karness/pkg/executor/kubeexec"
 	"github.com/d7561985/karness/pkg/worker"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -20,6 +27,8 @@ import (
 	"github.com/d7561985/karness/pkg/generated/informers/externalversions/karness/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
 )
@@ -39,11 +48,18 @@ const (
 	// MessageResourceSynced is the message used for an Event fired when a Foo
 	// is synced successfully
 	MessageResourceSynced = "Scenario synced successfully"
+
+	// mockKeyPrefix distinguishes mock service keys in work queue and harness from scenario keys
+	mockKeyPrefix = "mockservice:"
 )
 
 type service struct {
+	// kubeClientSet is a standard kubernetes clientset
+	kubeClientSet kubernetes.Interface
 	// sampleclientset is a clientset for our own API group
 	appClientSet versioned.Interface
+	// resolver maps kinds to dynamic clients of its resources
+	resolver kubeexec.Resolver
 
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
@@ -52,6 +68,9 @@ type service struct {
 	scenarioInformer v1alpha1.ScenarioInformer
 	scenarioSynced   cache.InformerSynced
 
+	mockInformer v1alpha1.MockServiceInformer
+	mockSynced   cache.InformerSynced
+
 	// workqueue is a rate limited work queue. This is used to queue work to be
 	// processed instead of performing it as soon as a change happens. This
 	// means we can ensure we only process a fixed amount of resources at a
@@ -59,10 +78,11 @@ type service struct {
 	// simultaneously in two different workers.
 	workqueue workqueue.RateLimitingInterface
 
-	harness harness.Harness
+	harness *harness.Harness
 }
 
-func New(sClient versioned.Interface, sInformer v1alpha1.ScenarioInformer) *service {
+func New(kClient kubernetes.Interface, dClient dynamic.Interface, sClient versioned.Interface,
+	sInformer v1alpha1.ScenarioInformer, mInformer v1alpha1.MockServiceInformer, opt ...harness.Option) *service {
 	// Create event broadcaster
 	// Add sample-controllers types to the default Kubernetes Scheme so Events can be
 	// logged for sample-controllers types.
@@ -71,31 +91,74 @@ func New(sClient versioned.Interface, sInformer v1alpha1.ScenarioInformer) *serv
 
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartStructuredLogging(0)
-	//eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: k.CoreV1().Events("")})
+	// events explain scenario failures without access to controller logs
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kClient.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(kscheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 
+	// discovery based mapper is reset when scenario uses kind which wasn't known yet
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kClient.Discovery()))
+
 	klog.Info("Setting up event handlers")
 
 	x := &service{
+		kubeClientSet:    kClient,
 		appClientSet:     sClient,
+		resolver:         kubeexec.NewResolver(dClient, mapper),
 		recorder:         recorder,
 		scenarioInformer: sInformer,
 		scenarioSynced:   sInformer.Informer().HasSynced,
+		mockInformer:     mInformer,
+		mockSynced:       mInformer.Informer().HasSynced,
 		workqueue:        worker.New(workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Scenarios")),
+		harness:          harness.New(opt...),
 	}
 
 	x.scenarioInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: x.enqueue,
+		AddFunc:    x.enqueue,
+		UpdateFunc: x.update,
+		DeleteFunc: x.delete,
+	})
+
+	x.mockInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: x.enqueueMock,
 		UpdateFunc: func(oldObj, newObj interface{}) {
-			x.delete(oldObj)
-			x.enqueue(newObj)
+			// status updates don't restart servers
+			if oldObj.(*api.MockService).Generation == newObj.(*api.MockService).Generation {
+				return
+			}
+
+			x.deleteMock(oldObj)
+			x.enqueueMock(newObj)
 		},
-		DeleteFunc: x.delete,
+		DeleteFunc: x.deleteMock,
 	})
 
 	return x
 }
 
+// enqueueMock puts prefixed key of mock service onto the work queue
+func (c *service) enqueueMock(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	c.workqueue.Add(mockKeyPrefix + key)
+}
+
+// deleteMock stops servers of mock service
+func (c *service) deleteMock(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	c.harness.Stop(mockKeyPrefix + key)
+	klog.Infof("delete mock service: %s", key)
+}
+
 // enqueueScenario takes a Foo resource and converts it into a namespace/name
 // string which is then put onto the work queue. This method should *not* be
 // passed resources of any type other than Foo.
@@ -110,6 +173,17 @@ func (c *service) enqueue(obj interface{}) {
 	c.workqueue.Add(key)
 }
 
+// update restarts processing when spec is changed.
+// Status updates and resyncs keep processor with its poll attempts, callback endpoints and subscriptions
+func (c *service) update(oldObj, newObj interface{}) {
+	if oldObj.(*api.Scenario).Generation == newObj.(*api.Scenario).Generation {
+		return
+	}
+
+	c.delete(oldObj)
+	c.enqueue(newObj)
+}
+
 // delete object from processing
 func (c *service) delete(obj interface{}) {
 	var key string
@@ -134,13 +208,14 @@ func (c *service) delete(obj interface{}) {
 func (c *service) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	defer c.harness.Close()
 
 	// Start the informer factories to begin populating the informer caches
 	klog.Info("Starting Foo controllers")
 
 	// Wait for the caches to be synced before starting workers
 	klog.Info("Waiting for informer caches to sync")
-	if ok := cache.WaitForCacheSync(stopCh, c.scenarioSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.scenarioSynced, c.mockSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
@@ -231,6 +306,10 @@ func (c *service) processNextWorkItem(ctx context.Context) bool {
 // converge the two. It then updates the Status block of the Foo resource
 // with the current status of the resource.
 func (c *service) syncHandler(ctx context.Context, key string) error {
+	if strings.HasPrefix(key, mockKeyPrefix) {
+		return c.syncMock(ctx, key)
+	}
+
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
@@ -255,8 +334,29 @@ func (c *service) syncHandler(ctx context.Context, key string) error {
 	return c.harness.Factory(ctx, c, key, asset)
 }
 
+// syncMock starts servers of mock service
+func (c *service) syncMock(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(strings.TrimPrefix(key, mockKeyPrefix))
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	mock, err := c.mockInformer.Lister().MockServices(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("mock service '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	return c.harness.Factory(ctx, c, key, mock)
+}
+
 func (c *service) Update(item *api.Scenario) error {
-	fmt.Println("STATUS", item.Status)
+	klog.V(4).Infof("scenario %s/%s status: %+v", item.Namespace, item.Name, item.Status)
 
 	// Finally, we update the status block of the Foo resource to reflect the
 	// current state of the world
@@ -284,3 +384,28 @@ func (c *service) updateScenarioStatus(item *api.Scenario) error {
 	_, err := c.appClientSet.KarnessV1alpha1().Scenarios(item.Namespace).UpdateStatus(context.TODO(), fooCopy, metav1.UpdateOptions{})
 	return err
 }
+
+func (c *service) Event(object runtime.Object, eventType, reason, message string) {
+	c.recorder.Event(object, eventType, reason, message)
+}
+
+func (c *service) UpdateMock(item *api.MockService) error {
+	_, err := c.appClientSet.KarnessV1alpha1().MockServices(item.Namespace).UpdateStatus(context.TODO(), item.DeepCopy(), metav1.UpdateOptions{})
+	return err
+}
+
+func (c *service) ConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	return c.kubeClientSet.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *service) Secret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	return c.kubeClientSet.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *service) Resource(ctx context.Context, gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error) {
+	return c.resolver(ctx, gvk, namespace)
+}
+
+func (c *service) Clientset() kubernetes.Interface {
+	return c.kubeClientSet
+}
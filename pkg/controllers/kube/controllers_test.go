@@ -2,14 +2,34 @@ package kube
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1/models/action"
+	"github.com/d7561985/karness/pkg/controllers/harness"
+	"github.com/d7561985/karness/pkg/executor/avroexec"
+	"github.com/d7561985/karness/pkg/executor/brokerexec"
+	"github.com/d7561985/karness/pkg/executor/callbackexec"
 	"github.com/d7561985/karness/pkg/executor/grpcexec"
+	"github.com/d7561985/karness/pkg/executor/pluginexec"
+	"github.com/d7561985/karness/pkg/executor/wsexec"
+	"github.com/golang/protobuf/proto"
+	"github.com/linkedin/goavro/v2"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
 	pb "google.golang.org/grpc/examples/helloworld/helloworld"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/diff"
 	"k8s.io/client-go/tools/cache"
@@ -20,6 +40,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
 
 	core "k8s.io/client-go/testing"
 )
@@ -32,22 +55,29 @@ var (
 type fixture struct {
 	t *testing.T
 
-	client *fake.Clientset
+	client        *fake.Clientset
+	kubeclient    *k8sfake.Clientset
+	dynamicclient *dynamicfake.FakeDynamicClient
 
 	// Objects to put in the store.
 	scenarioList []*v1alpha1.Scenario
+	mockList     []*v1alpha1.MockService
 
 	// Actions expected to happen on the client.
 	actions []core.Action
 
 	// Objects from here preloaded into NewSimpleFake.
-	objects []runtime.Object
+	objects     []runtime.Object
+	kubeobjects []runtime.Object
+
+	harnessOpt []harness.Option
 }
 
 func newFixture(t *testing.T) *fixture {
 	f := &fixture{}
 	f.t = t
 	f.objects = []runtime.Object{}
+	f.kubeobjects = []runtime.Object{}
 	return f
 }
 
@@ -83,15 +113,37 @@ func newScenario(
 
 func (f *fixture) newController() (*service, informers.SharedInformerFactory) {
 	f.client = fake.NewSimpleClientset(f.objects...)
+	f.kubeclient = k8sfake.NewSimpleClientset(f.kubeobjects...)
+	f.dynamicclient = dynamicfake.NewSimpleDynamicClient(kscheme.Scheme, f.kubeobjects...)
+
+	// discovery of resources available for kube actions
+	f.kubeclient.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"}},
+	}}
+
+	// recorder sends events to sink of all namespaces, tracker accepts them only in namespace of event
+	f.kubeclient.PrependReactor("create", "events", func(action core.Action) (bool, runtime.Object, error) {
+		event := action.(core.CreateAction).GetObject().(*corev1.Event)
+		err := f.kubeclient.Tracker().Create(corev1.SchemeGroupVersion.WithResource("events"), event, event.Namespace)
+
+		return true, event, err
+	})
 
 	i := informers.NewSharedInformerFactory(f.client, noResyncPeriodFunc())
-	c := New(f.client, i.Karness().V1alpha1().Scenarios())
+	c := New(f.kubeclient, f.dynamicclient, f.client,
+		i.Karness().V1alpha1().Scenarios(), i.Karness().V1alpha1().MockServices(), f.harnessOpt...)
 	c.scenarioSynced = alwaysReady
+	c.mockSynced = alwaysReady
 
 	for _, scenario := range f.scenarioList {
 		_ = i.Karness().V1alpha1().Scenarios().Informer().GetIndexer().Add(scenario)
 	}
 
+	for _, mock := range f.mockList {
+		_ = i.Karness().V1alpha1().MockServices().Informer().GetIndexer().Add(mock)
+	}
+
 	return c, i
 }
 
@@ -155,6 +207,8 @@ func filterInformerActions(actions []core.Action) []core.Action {
 		if len(act.GetNamespace()) == 0 &&
 			(act.Matches("list", "scenarios") ||
 				act.Matches("watch", "scenarios") ||
+				act.Matches("list", "mockservices") ||
+				act.Matches("watch", "mockservices") ||
 				act.Matches("list", "deployments") ||
 				act.Matches("watch", "deployments")) {
 			continue
@@ -293,7 +347,9 @@ func TestGRPCCall(t *testing.T) {
 `
 	l, srv := grpcexec.CreateMockServer(grpcexec.Fixture{
 		Res: &pb.HelloReply{Message: responseMSG},
-		CB:  func(req *pb.HelloRequest) {},
+		CB: func(req *pb.HelloRequest) {
+			assert.Equal(t, "hello", req.Name)
+		},
 	})
 
 	defer l.Close()
@@ -344,3 +400,1533 @@ func TestGRPCCall(t *testing.T) {
 
 	f.run(getKey(scena, t), 1)
 }
+
+func TestHTTPCall(t *testing.T) {
+	const expect = `{"message":"OK"}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(expect))
+	}))
+
+	defer srv.Close()
+
+	f := newFixture(t)
+
+	e := newEvent("http",
+		v1alpha1.Action{
+			Name: "Http-Test",
+			HTTP: &action.HTTP{
+				Addr:   srv.URL,
+				Method: http.MethodPost,
+			},
+			Body: v1alpha1.Body{
+				KV: map[string]v1alpha1.Any{
+					"name": "hello",
+				},
+			},
+			BindResult: map[string]string{"MSG": `{.message}`},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "200",
+				Body: v1alpha1.Body{
+					KV: map[string]v1alpha1.Any{
+						"message": "OK",
+					},
+				},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+func TestFieldMatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"id":%q,"created":%q,"items":[{"qty":1},{"qty":2}]}`,
+			r.URL.Query().Get("id"), time.Now().Format(time.RFC3339Nano))))
+	}))
+
+	defer srv.Close()
+
+	f := newFixture(t)
+
+	e := newEvent("http",
+		v1alpha1.Action{
+			Name: "Http-Test",
+			HTTP: &action.HTTP{Addr: srv.URL + "?id=a1b2", Method: http.MethodGet},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "200",
+				KV: &v1alpha1.KV{Field: []v1alpha1.KVFieldMatch{
+					{Key: "{.id}", Value: "a1b2"},
+					{Key: "{.created}", Op: v1alpha1.OpRegex, Value: `^\d{4}-`},
+					{Key: "{.items}", Op: v1alpha1.OpLength, Value: "2"},
+					{Key: "{.items[*].qty}", Op: v1alpha1.OpGt, Value: "0"},
+					{Key: "{.error}", Op: v1alpha1.OpNotExists},
+				}},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+func TestSubsetMatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"order":{"id":7,"paid":true,"created":%q,"items":[{"sku":"x","qty":1}]}}`,
+			time.Now().Format(time.RFC3339Nano))))
+	}))
+
+	defer srv.Close()
+
+	f := newFixture(t)
+
+	e := newEvent("http",
+		v1alpha1.Action{
+			Name: "Http-Test",
+			HTTP: &action.HTTP{Addr: srv.URL, Method: http.MethodGet},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "200",
+				Subset: runtime.RawExtension{Raw: []byte(`{"order":{"id":7,"paid":true,"items":[{"sku":"x"}]}}`)},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+func TestSchemaCondition(t *testing.T) {
+	const schema = `{
+  "type": "object",
+  "required": ["id", "items"],
+  "properties": {
+    "id": {"type": "integer"},
+    "items": {"type": "array", "items": {"type": "object", "required": ["sku"]}}
+  }
+}`
+
+	tests := []struct {
+		name    string
+		schema  v1alpha1.ConditionSchema
+		body    string
+		state   v1alpha1.State
+		message string
+	}{
+		{"config map", v1alpha1.ConditionSchema{ConfigMap: "contracts", Key: "order.json"},
+			`{"id":7,"items":[{"sku":"x"}],"created":"2021-03-06T13:21:28Z"}`, v1alpha1.Complete, ""},
+		{"inline drift", v1alpha1.ConditionSchema{Inline: runtime.RawExtension{Raw: []byte(schema)}},
+			`{"id":"7","items":[{"qty":1}]}`, v1alpha1.Failed,
+			`schema: id: Invalid type. Expected: integer, given: string; items.0: sku is required`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(test.body))
+			}))
+
+			defer srv.Close()
+
+			f := newFixture(t)
+			f.kubeobjects = append(f.kubeobjects, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "contracts", Namespace: metav1.NamespaceDefault},
+				Data:       map[string]string{"order.json": schema},
+			})
+
+			e := newEvent("http",
+				v1alpha1.Action{
+					Name: "Http-Test",
+					HTTP: &action.HTTP{Addr: srv.URL, Method: http.MethodGet},
+				},
+				v1alpha1.Condition{
+					Schema: &test.schema,
+				},
+			)
+
+			scena := newScenario("test", "", "", nil, e)
+			f.scenarioList = append(f.scenarioList, scena)
+			f.objects = append(f.objects, scena)
+
+			res := newScenario("test", test.state, "1 of 1", nil, e)
+			if test.state == v1alpha1.Failed {
+				res.Status.Progress = "0 of 1"
+				res.Status.Message = `event "event-http": condition 0: complete condition doesn't match: ` + test.message
+				res.Status.Mismatch = &v1alpha1.Mismatch{Event: "event-http", Message: test.message}
+			}
+
+			f.expectUpdateFooStatusAction(newScenario("test", v1alpha1.Ready, "0 of 1", nil, e), res)
+
+			f.run(getKey(scena, t), 1)
+		})
+	}
+}
+
+func TestCELCondition(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[{"sku":"x","price":9.5},{"sku":"y","price":120}]}`))
+	}))
+
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		cel     string
+		state   v1alpha1.State
+		message string
+	}{
+		{"cross field", `code == "200" && headers["Content-Type"] == "application/json" && ` +
+			`body.items.size() > 0 && body.items[0].price < vars.limit && latency < duration("5s")`, v1alpha1.Complete, ""},
+		{"mismatch", `body.items.all(i, i.price < vars.limit)`, v1alpha1.Failed,
+			`expression is false: body.items.all(i, i.price < vars.limit)`},
+		{"evaluation error", `body.total > 0`, v1alpha1.Failed, `cel: no such key: total`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFixture(t)
+
+			vars := map[string]v1alpha1.Any{"limit": "100"}
+
+			e := newEvent("http",
+				v1alpha1.Action{
+					Name: "Http-Test",
+					HTTP: &action.HTTP{Addr: srv.URL, Method: http.MethodGet},
+				},
+				v1alpha1.Condition{CEL: test.cel},
+			)
+
+			scena := newScenario("test", "", "", vars, e)
+			f.scenarioList = append(f.scenarioList, scena)
+			f.objects = append(f.objects, scena)
+
+			res := newScenario("test", test.state, "1 of 1", vars, e)
+			if test.state == v1alpha1.Failed {
+				res.Status.Progress = "0 of 1"
+				res.Status.Message = `event "event-http": condition 0: complete condition doesn't match: ` + test.message
+				res.Status.Mismatch = &v1alpha1.Mismatch{Event: "event-http", Message: test.message}
+			}
+
+			f.expectUpdateFooStatusAction(newScenario("test", v1alpha1.Ready, "0 of 1", vars, e), res)
+
+			f.run(getKey(scena, t), 1)
+		})
+	}
+}
+
+func TestCELConditionInvalid(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	f := newFixture(t)
+
+	e := newEvent("noop", v1alpha1.Action{Name: "Noop"}, v1alpha1.Condition{CEL: `code == `})
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	c, _ := f.newController()
+
+	if !assert.NoError(t, c.syncHandler(ctx, getKey(scena, t))) {
+		return
+	}
+
+	// expressions are compiled when scenario starts
+	assert.Eventually(t, func() bool {
+		res, err := f.client.KarnessV1alpha1().Scenarios(metav1.NamespaceDefault).Get(ctx, "test", metav1.GetOptions{})
+		return err == nil && res.Status.State == v1alpha1.Failed &&
+			strings.HasPrefix(res.Status.Message, `event "event-noop" condition 0: cel: ERROR: <input>:1:9: Syntax error`)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestFieldMatchInvalid(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	f := newFixture(t)
+
+	e := newEvent("http",
+		v1alpha1.Action{
+			Name: "Http-Test",
+			HTTP: &action.HTTP{Addr: "http://localhost", Method: http.MethodGet},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				KV: &v1alpha1.KV{Field: []v1alpha1.KVFieldMatch{{Key: "{.id}", Op: "like", Value: "a1b2"}}},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	c, _ := f.newController()
+
+	if !assert.NoError(t, c.syncHandler(ctx, getKey(scena, t))) {
+		return
+	}
+
+	// matchers are validated when scenario starts
+	assert.Eventually(t, func() bool {
+		res, err := f.client.KarnessV1alpha1().Scenarios(metav1.NamespaceDefault).Get(ctx, "test", metav1.GetOptions{})
+		return err == nil && res.Status.State == v1alpha1.Failed &&
+			res.Status.Message == `event "event-http" condition 0: field match 0: "like": unknown operator`
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestGRPCStreamCall(t *testing.T) {
+	expect := `[{"message":"A"},{"message":"B"}]`
+
+	l, srv := grpcexec.CreateEchoServer(grpcexec.EchoFixture{})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	f := newFixture(t)
+
+	e := newEvent("grpc-stream",
+		v1alpha1.Action{
+			Name: "Grpc-Stream-Test",
+			GRPC: &action.GRPC{
+				Addr:    l.Addr().String(),
+				Package: "grpc.examples.echo",
+				Service: "Echo",
+				RPC:     "BidirectionalStreamingEcho",
+				Stream: &action.Stream{
+					Messages: []runtime.RawExtension{
+						{Raw: []byte(`{"message":"A"}`)},
+						{Raw: []byte(`{"message":"B"}`)},
+					},
+				},
+			},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: codes.OK.String(),
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+func TestGRPCCallDescriptorsConfigMap(t *testing.T) {
+	const helloworldProto = `syntax = "proto3";
+package helloworld;
+service Greeter {
+  rpc SayHello (HelloRequest) returns (HelloReply) {}
+}
+message HelloRequest {
+  string name = 1;
+}
+message HelloReply {
+  string message = 1;
+}
+`
+
+	l, srv := grpcexec.CreateMockServer(grpcexec.Fixture{
+		Res: &pb.HelloReply{Message: "OK"},
+		CB: func(req *pb.HelloRequest) {
+			assert.Equal(t, "hello", req.Name)
+		},
+		NoReflection: true,
+	})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	f := newFixture(t)
+	f.kubeobjects = append(f.kubeobjects, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "protos", Namespace: metav1.NamespaceDefault},
+		Data:       map[string]string{"helloworld.proto": helloworldProto},
+	})
+
+	e := newEvent("grpc",
+		v1alpha1.Action{
+			Name: "Grpc-Test",
+			GRPC: &action.GRPC{
+				Addr:        l.Addr().String(),
+				Package:     "helloworld",
+				Service:     "Greeter",
+				RPC:         "SayHello",
+				Descriptors: &action.Descriptors{ConfigMap: "protos"},
+			},
+			Body: v1alpha1.Body{
+				KV: map[string]v1alpha1.Any{
+					"name": "hello",
+				},
+			},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: codes.OK.String(),
+				Body: v1alpha1.Body{
+					KV: map[string]v1alpha1.Any{
+						"message": "OK",
+					},
+				},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+func TestGRPCCallTLSSecret(t *testing.T) {
+	fx, err := grpcexec.CreateTLSFixture()
+	assert.NoError(t, err)
+
+	l, srv := grpcexec.CreateMockServer(grpcexec.Fixture{
+		Res: &pb.HelloReply{Message: "OK"},
+		CB:  func(req *pb.HelloRequest) {},
+		TLS: fx.Server,
+	})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	f := newFixture(t)
+	f.kubeobjects = append(f.kubeobjects, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mtls", Namespace: metav1.NamespaceDefault},
+		Data: map[string][]byte{
+			corev1.ServiceAccountRootCAKey: fx.CA,
+			corev1.TLSCertKey:              fx.ClientCert,
+			corev1.TLSPrivateKeyKey:        fx.ClientKey,
+		},
+	})
+
+	e := newEvent("grpc",
+		v1alpha1.Action{
+			Name: "Grpc-Test",
+			GRPC: &action.GRPC{
+				Addr:    l.Addr().String(),
+				Package: "helloworld",
+				Service: "Greeter",
+				RPC:     "SayHello",
+				TLS: &action.TLS{
+					Secret:     "mtls",
+					ServerName: grpcexec.TLSServerName,
+				},
+			},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: codes.OK.String(),
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+func TestPluginCall(t *testing.T) {
+	expect := `{"name":"hello"}`
+
+	l, srv := pluginexec.CreateMockPlugin()
+
+	defer l.Close()
+	defer srv.Stop()
+
+	p, err := pluginexec.Dial(context.Background(), l.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pluginexec.Register("mock", p)
+	defer pluginexec.CloseAll()
+
+	f := newFixture(t)
+
+	e := newEvent("plugin",
+		v1alpha1.Action{
+			Name: "Plugin-Test",
+			Plugin: &action.Plugin{
+				Name:   "mock",
+				Config: runtime.RawExtension{Raw: []byte(`{"code":"OK"}`)},
+			},
+			Body: v1alpha1.Body{
+				KV: map[string]v1alpha1.Any{
+					"name": "hello",
+				},
+			},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "OK",
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+func TestKubeCall(t *testing.T) {
+	f := newFixture(t)
+
+	create := newEvent("kube-create",
+		v1alpha1.Action{
+			Name: "Kube-Create-Test",
+			Kube: &action.Kube{
+				Verb: "create",
+				Manifest: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kube-test
+data:
+  key: value
+`,
+			},
+			BindResult: map[string]string{"CM": `{.metadata.name}`},
+		},
+		v1alpha1.Condition{Response: &v1alpha1.ConditionResponse{Status: "OK"}},
+	)
+
+	get := newEvent("kube-get",
+		v1alpha1.Action{
+			Name: "Kube-Get-Test",
+			Kube: &action.Kube{
+				Verb:     "get",
+				Manifest: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"missing"}}`,
+			},
+		},
+		v1alpha1.Condition{Response: &v1alpha1.ConditionResponse{Status: "NotFound"}},
+	)
+
+	scena := newScenario("test", "", "", nil, create, get)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 2", nil, create, get),
+		newScenario("test", v1alpha1.InProgress, "1 of 2", nil, create, get),
+		newScenario("test", v1alpha1.Complete, "2 of 2", nil, create, get),
+	)
+
+	f.run(getKey(scena, t), 2)
+
+	cm, err := f.dynamicclient.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).
+		Namespace(metav1.NamespaceDefault).Get(context.Background(), "kube-test", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"key": "value"}, cm.Object["data"])
+}
+
+func TestSQLCall(t *testing.T) {
+	expect := `[{"id":1,"name":"alice"}]`
+	dsn := filepath.Join(t.TempDir(), "test.db")
+
+	db, err := sql.Open("sqlite3", dsn)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT); INSERT INTO users (name) VALUES ('alice'), ('bob')`)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+
+	f := newFixture(t)
+	f.kubeobjects = append(f.kubeobjects, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: metav1.NamespaceDefault},
+		Data:       map[string][]byte{"dsn": []byte(dsn)},
+	})
+
+	e := newEvent("sql",
+		v1alpha1.Action{
+			Name: "Sql-Test",
+			SQL: &action.SQL{
+				Driver: "sqlite3",
+				Secret: "db",
+				Query:  `SELECT id, name FROM users WHERE name = ?`,
+				Args:   []string{"alice"},
+			},
+			BindResult: map[string]string{"USER_ID": `{[0].id}`},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "OK",
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+// respond emulates service which publishes messages to nats when it receives message of topic
+func respond(ctx context.Context, t *testing.T, url, topic string, msgs ...brokerexec.Message) {
+	b, err := brokerexec.Open(ctx, brokerexec.NATS, url)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	sub, err := b.Subscribe(ctx, brokerexec.ConsumeRequest{Topic: topic})
+	if !assert.NoError(t, err) {
+		_ = b.Close()
+		return
+	}
+
+	go func() {
+		defer b.Close()
+
+		for {
+			if _, err := sub.Next(ctx, brokerexec.ConsumeRequest{}); err != nil {
+				return
+			}
+
+			for _, m := range msgs {
+				assert.NoError(t, b.Publish(ctx, m))
+			}
+		}
+	}()
+}
+
+// runSubscribed waits until consume actions of scenario are subscribed before steps
+func runSubscribed(t *testing.T, f *fixture, srv *brokerexec.NATSServer, key string, steps int, topics ...string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, _ := f.newController()
+	if !assert.NoError(t, c.syncHandler(ctx, key)) {
+		return
+	}
+
+	assert.Eventually(t, func() bool {
+		for _, topic := range topics {
+			if !srv.Subscribed(topic) {
+				return false
+			}
+		}
+
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	p, ok := c.harness.GetProcessor(key)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	for step := 0; step < steps; step++ {
+		p.(interface {
+			Step(ctx context.Context) bool
+		}).Step(ctx)
+	}
+}
+
+func TestBrokerCall(t *testing.T) {
+	expect := `{"id":2,"status":"done"}`
+
+	srv := brokerexec.CreateNATSServer()
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// service replies to command before consume event starts
+	respond(ctx, t, srv.URL(), "commands",
+		brokerexec.Message{Topic: "orders", Value: []byte(`{"id":1,"status":"new"}`)},
+		brokerexec.Message{Topic: "orders", Value: []byte(expect)},
+	)
+
+	f := newFixture(t)
+
+	publish := newEvent("broker-publish",
+		v1alpha1.Action{
+			Name: "Broker-Publish-Test",
+			Broker: &action.Broker{
+				Kind:    brokerexec.NATS,
+				Addr:    srv.URL(),
+				Topic:   "commands",
+				Publish: &action.Publish{Header: map[string]string{"Trace": "abc"}},
+			},
+			Body: v1alpha1.Body{KV: map[string]v1alpha1.Any{"id": "2"}},
+		},
+		v1alpha1.Condition{Response: &v1alpha1.ConditionResponse{Status: "OK"}},
+	)
+
+	consume := newEvent("broker-consume",
+		v1alpha1.Action{
+			Name: "Broker-Consume-Test",
+			Broker: &action.Broker{
+				Kind:  brokerexec.NATS,
+				Addr:  srv.URL(),
+				Topic: "orders",
+				Consume: &action.Consume{
+					Filter:  map[string]string{"{.id}": "2"},
+					Timeout: metav1.Duration{Duration: time.Second},
+				},
+			},
+			BindResult: map[string]string{"STATUS": "{.status}"},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "OK",
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, publish, consume)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	runSubscribed(t, f, srv, getKey(scena, t), 2, "orders")
+
+	res, err := f.client.KarnessV1alpha1().Scenarios(metav1.NamespaceDefault).Get(ctx, "test", metav1.GetOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, v1alpha1.Complete, res.Status.State)
+	assert.Equal(t, "2 of 2", res.Status.Progress)
+}
+
+func TestBrokerDecode(t *testing.T) {
+	const (
+		helloworldProto = `syntax = "proto3";
+package helloworld;
+message HelloReply {
+  string message = 1;
+}
+`
+		userSchema = `{"type":"record","name":"User","fields":[{"name":"name","type":"string"}]}`
+	)
+
+	protoMsg, err := proto.Marshal(&pb.HelloReply{Message: "OK"})
+	assert.NoError(t, err)
+
+	codec, err := goavro.NewCodec(userSchema)
+	assert.NoError(t, err)
+
+	avroMsg, err := codec.BinaryFromNative(nil, map[string]interface{}{"name": "alice"})
+	assert.NoError(t, err)
+
+	registry := avroexec.CreateMockRegistry(avroexec.RegistryFixture{Schemas: map[int]string{1: userSchema}})
+	defer registry.Close()
+
+	srv := brokerexec.CreateNATSServer()
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	respond(ctx, t, srv.URL(), "commands",
+		brokerexec.Message{Topic: "replies", Value: protoMsg},
+		brokerexec.Message{Topic: "users", Value: avroexec.Frame(1, avroMsg)},
+	)
+
+	f := newFixture(t)
+	f.kubeobjects = append(f.kubeobjects, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "protos", Namespace: metav1.NamespaceDefault},
+		Data:       map[string]string{"helloworld.proto": helloworldProto},
+	})
+
+	command := newEvent("broker-publish",
+		v1alpha1.Action{
+			Name:   "Broker-Publish-Test",
+			Broker: &action.Broker{Kind: brokerexec.NATS, Addr: srv.URL(), Topic: "commands", Publish: &action.Publish{}},
+			Body:   v1alpha1.Body{KV: map[string]v1alpha1.Any{"id": "1"}},
+		},
+		v1alpha1.Condition{Response: &v1alpha1.ConditionResponse{Status: "OK"}},
+	)
+
+	protobuf := newEvent("broker-protobuf",
+		v1alpha1.Action{
+			Name: "Broker-Protobuf-Test",
+			Broker: &action.Broker{
+				Kind:  brokerexec.NATS,
+				Addr:  srv.URL(),
+				Topic: "replies",
+				Consume: &action.Consume{
+					Filter:  map[string]string{"{.message}": "OK"},
+					Timeout: metav1.Duration{Duration: time.Second},
+					Decode: &action.Decode{
+						Protobuf:    "helloworld.HelloReply",
+						Descriptors: &action.Descriptors{ConfigMap: "protos"},
+					},
+				},
+			},
+		},
+		v1alpha1.Condition{Response: &v1alpha1.ConditionResponse{Status: "OK"}},
+	)
+
+	avro := newEvent("broker-avro",
+		v1alpha1.Action{
+			Name: "Broker-Avro-Test",
+			Broker: &action.Broker{
+				Kind:  brokerexec.NATS,
+				Addr:  srv.URL(),
+				Topic: "users",
+				Consume: &action.Consume{
+					Filter:  map[string]string{"{.name}": "alice"},
+					Timeout: metav1.Duration{Duration: time.Second},
+					Decode:  &action.Decode{Avro: &action.Avro{Registry: registry.URL}},
+				},
+			},
+		},
+		v1alpha1.Condition{Response: &v1alpha1.ConditionResponse{Status: "OK"}},
+	)
+
+	scena := newScenario("test", "", "", nil, command, protobuf, avro)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	runSubscribed(t, f, srv, getKey(scena, t), 3, "replies", "users")
+
+	res, err := f.client.KarnessV1alpha1().Scenarios(metav1.NamespaceDefault).Get(ctx, "test", metav1.GetOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, v1alpha1.Complete, res.Status.State)
+	assert.Equal(t, "3 of 3", res.Status.Progress)
+}
+
+func TestStarlarkCall(t *testing.T) {
+	expect := `{"greeting":"hello alice"}`
+
+	f := newFixture(t)
+
+	vars := map[string]v1alpha1.Any{"USER": "alice"}
+
+	set := newEvent("set",
+		v1alpha1.Action{
+			Name: "Starlark-Set",
+			Starlark: &action.Starlark{
+				Script: `vars.set("GREETING", "hello " + vars.get("USER"))`,
+			},
+		},
+	)
+
+	get := newEvent("get",
+		v1alpha1.Action{
+			Name: "Starlark-Get",
+			Starlark: &action.Starlark{
+				Script: `body = {"greeting": vars.get("GREETING")}`,
+			},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "OK",
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", vars, set, get)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 2", vars, set, get),
+		newScenario("test", v1alpha1.InProgress, "1 of 2", vars, set, get),
+		newScenario("test", v1alpha1.Complete, "2 of 2", vars, set, get),
+	)
+
+	f.run(getKey(scena, t), 2)
+}
+
+func TestHealthCall(t *testing.T) {
+	expect := `{"status":"SERVING"}`
+
+	l, s, h := grpcexec.CreateHealthServer()
+	defer s.Stop()
+
+	h.SetServingStatus("orders", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		h.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	}()
+
+	f := newFixture(t)
+
+	e := newEvent("health",
+		v1alpha1.Action{
+			Name: "Wait-Orders",
+			Health: &action.Health{
+				Addr:     l.Addr().String(),
+				Service:  "orders",
+				Timeout:  metav1.Duration{Duration: 5 * time.Second},
+				Interval: metav1.Duration{Duration: 20 * time.Millisecond},
+			},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "OK",
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+func TestPollUntil(t *testing.T) {
+	expect := `{"n":3}`
+
+	f := newFixture(t)
+
+	e := newEvent("poll",
+		v1alpha1.Action{
+			Name: "Counter",
+			Starlark: &action.Starlark{Script: `
+n = int(vars.get("N", "0")) + 1
+vars.set("N", str(n))
+body = {"n": n}
+`},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "OK",
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+	e.Poll = &v1alpha1.Poll{Interval: metav1.Duration{Duration: time.Minute}, Attempts: 5}
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	attempts := make([]*v1alpha1.Scenario, 0, 2)
+
+	for _, n := range []string{"1", "2"} {
+		s := newScenario("test", v1alpha1.InProgress, "0 of 1", nil, e)
+		s.Status.Mismatch = &v1alpha1.Mismatch{
+			Event:   "event-poll",
+			Message: "body mismatch",
+			Diff:    "--- expected\n+++ actual\n@@ -1,3 +1,3 @@\n {\n-  \"n\": 3\n+  \"n\": " + n + "\n }\n",
+		}
+
+		attempts = append(attempts, s)
+	}
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		attempts[0],
+		attempts[1],
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 3)
+}
+
+func TestPollExhausted(t *testing.T) {
+	expect := `{"n":3}`
+
+	f := newFixture(t)
+
+	e := newEvent("poll",
+		v1alpha1.Action{
+			Name:     "Counter",
+			Starlark: &action.Starlark{Script: `body = {"n": 1}`},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "OK",
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+	e.Poll = &v1alpha1.Poll{Interval: metav1.Duration{Duration: time.Minute}, Attempts: 2}
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	mismatch := &v1alpha1.Mismatch{
+		Event:   "event-poll",
+		Message: "body mismatch",
+		Diff:    "--- expected\n+++ actual\n@@ -1,3 +1,3 @@\n {\n-  \"n\": 3\n+  \"n\": 1\n }\n",
+	}
+
+	attempt := newScenario("test", v1alpha1.InProgress, "0 of 1", nil, e)
+	attempt.Status.Mismatch = mismatch
+
+	failed := newScenario("test", v1alpha1.Failed, "0 of 1", nil, e)
+	failed.Status.Message = `event "event-poll": 2 attempts exhausted: condition 0: complete condition doesn't match: body mismatch`
+	failed.Status.Mismatch = mismatch
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		attempt,
+		failed,
+	)
+
+	f.run(getKey(scena, t), 2)
+	// unchanged mismatch of the second attempt isn't recorded again
+	assert.Eventually(t, func() bool {
+		events, err := f.kubeclient.CoreV1().Events(metav1.NamespaceDefault).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return false
+		}
+
+		var mismatches []corev1.Event
+
+		for _, event := range events.Items {
+			if event.Reason == harness.ReasonConditionMismatch {
+				mismatches = append(mismatches, event)
+			}
+		}
+
+		return len(mismatches) == 1 && mismatches[0].Type == corev1.EventTypeWarning && mismatches[0].Count == 1 &&
+			mismatches[0].Message == `event "event-poll" condition 0: complete condition doesn't match: body mismatch`
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// informerUpdate calls update handler like informer does and processes queued keys like worker does
+func informerUpdate(ctx context.Context, c *service, oldObj, newObj *v1alpha1.Scenario) {
+	c.update(oldObj, newObj)
+
+	for c.workqueue.Len() > 0 {
+		c.processNextWorkItem(ctx)
+	}
+}
+
+func TestPollKeptOnUpdate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expect := `{"n":3}`
+
+	f := newFixture(t)
+
+	e := newEvent("poll",
+		v1alpha1.Action{
+			Name:     "Counter",
+			Starlark: &action.Starlark{Script: `body = {"n": 1}`},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "OK",
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+	e.Poll = &v1alpha1.Poll{Interval: metav1.Duration{Duration: time.Minute}, Attempts: 2}
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	c, _ := f.newController()
+	key := getKey(scena, t)
+
+	if !assert.NoError(t, c.syncHandler(ctx, key)) {
+		return
+	}
+
+	p, ok := c.harness.GetProcessor(key)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	step := p.(interface {
+		Step(ctx context.Context) bool
+	}).Step
+
+	step(ctx)
+
+	updated, err := f.client.KarnessV1alpha1().Scenarios(metav1.NamespaceDefault).Get(ctx, "test", metav1.GetOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// status update and resync don't restart processor with fresh attempts
+	informerUpdate(ctx, c, scena, updated)
+	informerUpdate(ctx, c, updated, updated)
+
+	cur, _ := c.harness.GetProcessor(key)
+	assert.Same(t, p, cur)
+
+	step(ctx)
+
+	res, err := f.client.KarnessV1alpha1().Scenarios(metav1.NamespaceDefault).Get(ctx, "test", metav1.GetOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, v1alpha1.Failed, res.Status.State)
+	assert.Contains(t, res.Status.Message, "2 attempts exhausted")
+
+	// changed spec restarts processor
+	changed := res.DeepCopy()
+	changed.Generation++
+
+	informerUpdate(ctx, c, res, changed)
+
+	cur, _ = c.harness.GetProcessor(key)
+	assert.NotSame(t, p, cur)
+}
+
+func TestGraphQLCall(t *testing.T) {
+	expect := `{"data":{"user":{"id":"42"}},"errors":[]}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		_, _ = fmt.Fprintf(w, `{"data":{"user":{"id":%q}}}`, req.Variables["id"])
+	}))
+
+	defer srv.Close()
+
+	f := newFixture(t)
+
+	vars := map[string]v1alpha1.Any{"USER_ID": "42"}
+
+	e := newEvent("graphql",
+		v1alpha1.Action{
+			Name: "GraphQL-Test",
+			GraphQL: &action.GraphQL{
+				Addr:          srv.URL,
+				Query:         `query User($id: ID!) { user(id: $id) { id } }`,
+				OperationName: "User",
+				Variables:     runtime.RawExtension{Raw: []byte(`{"id":"{{ .USER_ID }}"}`)},
+			},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "OK",
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", vars, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", vars, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", vars, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+func TestWebSocketCall(t *testing.T) {
+	// string message is sent as text, so echoed message is JSON
+	expect := `[{"type":"welcome"},{"type":"subscribe","id":7},{"type":"ping"},{"type":"update","id":7}]`
+
+	srv := wsexec.CreateMockServer(`{"type":"welcome"}`)
+	defer srv.Close()
+
+	f := newFixture(t)
+
+	e := newEvent("websocket",
+		v1alpha1.Action{
+			Name: "WebSocket-Test",
+			WebSocket: &action.WebSocket{
+				Addr: "ws" + strings.TrimPrefix(srv.URL, "http"),
+				Messages: []runtime.RawExtension{
+					{Raw: []byte(`{"type":"subscribe","id":7}`)},
+					{Raw: []byte(`"{\"type\":\"ping\"}"`)},
+					{Raw: []byte(`{"type":"update","id":7}`)},
+				},
+				Duration: metav1.Duration{Duration: 5 * time.Second},
+				Match:    map[string]string{"{.type}": "update"},
+			},
+		},
+		v1alpha1.Condition{
+			Response: &v1alpha1.ConditionResponse{
+				Status: "OK",
+				Body:   v1alpha1.Body{JSON: &expect},
+			},
+		},
+	)
+
+	scena := newScenario("test", "", "", nil, e)
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	f.expectUpdateFooStatusAction(
+		newScenario("test", v1alpha1.Ready, "0 of 1", nil, e),
+		newScenario("test", v1alpha1.Complete, "1 of 1", nil, e),
+	)
+
+	f.run(getKey(scena, t), 1)
+}
+
+func TestMockService(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expect := `{"id":1}`
+
+	mock := &v1alpha1.MockService{
+		TypeMeta:   v1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: v1.ObjectMeta{Name: "users", Namespace: metav1.NamespaceDefault},
+		Spec: v1alpha1.MockServiceSpec{
+			HTTP: &v1alpha1.MockHTTP{},
+			Rules: []v1alpha1.MockRule{{
+				Method:   "POST /users",
+				Match:    map[string]string{"{.name}": "alice"},
+				Response: v1alpha1.MockResponse{Code: "201", Body: v1alpha1.Body{JSON: &expect}},
+			}},
+		},
+	}
+
+	f := newFixture(t)
+	f.mockList = append(f.mockList, mock)
+	f.objects = append(f.objects, mock)
+
+	c, i := f.newController()
+
+	if !assert.NoError(t, c.syncHandler(ctx, mockKeyPrefix+"default/users")) {
+		return
+	}
+
+	var addr string
+
+	// reported address is reachable, not listen address of all interfaces
+	assert.Eventually(t, func() bool {
+		m, err := f.client.KarnessV1alpha1().MockServices(metav1.NamespaceDefault).Get(ctx, "users", metav1.GetOptions{})
+		if err != nil || m.Status.State != v1alpha1.Serving {
+			return false
+		}
+
+		host, _, err := net.SplitHostPort(m.Status.HTTP)
+		addr = m.Status.HTTP
+
+		return err == nil && !net.ParseIP(host).IsUnspecified()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	scena := newScenario("test", "", "", nil,
+		newEvent("call",
+			v1alpha1.Action{
+				Name: "Create-User",
+				HTTP: &action.HTTP{
+					Addr:   "http://" + addr + "/users",
+					Method: http.MethodPost,
+				},
+				Body: v1alpha1.Body{KV: map[string]v1alpha1.Any{"name": "alice"}},
+			},
+			v1alpha1.Condition{
+				Response: &v1alpha1.ConditionResponse{Status: "201", Body: v1alpha1.Body{JSON: &expect}},
+			},
+		),
+		newEvent("calls",
+			v1alpha1.Action{
+				Name:       "Recorded-Calls",
+				Mock:       &action.Mock{Service: "users", Method: "POST /users"},
+				BindResult: map[string]string{"CALLED": `{[0].request.name}`},
+			},
+		),
+		newEvent("check",
+			v1alpha1.Action{
+				Name:     "Check",
+				Starlark: &action.Starlark{Script: `code = "OK" if vars.get("CALLED") == "alice" else "NotCalled"`},
+			},
+			v1alpha1.Condition{Response: &v1alpha1.ConditionResponse{Status: "OK"}},
+		),
+	)
+
+	// scenario is created when mock service is running
+	assert.NoError(t, f.client.Tracker().Add(scena))
+	assert.NoError(t, i.Karness().V1alpha1().Scenarios().Informer().GetIndexer().Add(scena))
+
+	if !assert.NoError(t, c.syncHandler(ctx, getKey(scena, t))) {
+		return
+	}
+
+	p, ok := c.harness.GetProcessor(getKey(scena, t))
+	if !assert.True(t, ok) {
+		return
+	}
+
+	for step := 0; step < 3; step++ {
+		p.(interface {
+			Step(ctx context.Context) bool
+		}).Step(ctx)
+	}
+
+	res, err := f.client.KarnessV1alpha1().Scenarios(metav1.NamespaceDefault).Get(ctx, "test", metav1.GetOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, v1alpha1.Complete, res.Status.State)
+	assert.Equal(t, "3 of 3", res.Status.Progress)
+
+	c.harness.Stop(mockKeyPrefix + "default/users")
+}
+
+func TestCallbackCall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expect := `{"id":7,"status":"done"}`
+
+	srv := httptest.NewUnstartedServer(nil)
+	callbacks := callbackexec.New("http://" + srv.Listener.Addr().String())
+	srv.Config.Handler = callbacks
+	srv.Start()
+
+	defer srv.Close()
+
+	// service accepts order and notifies webhook asynchronously
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		go func() {
+			for _, status := range []string{"pending", "done"} {
+				res, err := http.Post(req.Variables["webhook"], "application/json",
+					strings.NewReader(`{"id":7,"status":"`+status+`"}`))
+				if assert.NoError(t, err) {
+					_ = res.Body.Close()
+				}
+			}
+		}()
+
+		_, _ = w.Write([]byte(`{"data":{"accepted":true}}`))
+	}))
+
+	defer service.Close()
+
+	f := newFixture(t)
+	f.harnessOpt = append(f.harnessOpt, harness.WithCallbacks(callbacks))
+
+	scena := newScenario("test", "", "", nil,
+		newEvent("order",
+			v1alpha1.Action{
+				Name: "Order",
+				GraphQL: &action.GraphQL{
+					Addr:      service.URL,
+					Query:     `mutation Order($webhook: String!) { order(webhook: $webhook) }`,
+					Variables: runtime.RawExtension{Raw: []byte(`{"webhook":"{{ .CALLBACK_URL }}"}`)},
+				},
+			},
+			v1alpha1.Condition{Response: &v1alpha1.ConditionResponse{Status: "OK"}},
+		),
+		newEvent("callback",
+			v1alpha1.Action{
+				Name: "Wait-Done",
+				Callback: &action.Callback{
+					Variable: "CALLBACK_URL",
+					Method:   http.MethodPost,
+					Match:    map[string]string{"{.status}": "done"},
+					Timeout:  metav1.Duration{Duration: 5 * time.Second},
+				},
+			},
+			v1alpha1.Condition{
+				Response: &v1alpha1.ConditionResponse{Status: "OK", Body: v1alpha1.Body{JSON: &expect}},
+			},
+		),
+	)
+
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	c, _ := f.newController()
+	key := getKey(scena, t)
+
+	if !assert.NoError(t, c.syncHandler(ctx, key)) {
+		return
+	}
+
+	// endpoint is registered when scenario starts
+	assert.Eventually(t, func() bool {
+		_, ok := callbacks.Get(key, "CALLBACK_URL")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	p, ok := c.harness.GetProcessor(key)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	for step := 0; step < 2; step++ {
+		p.(interface {
+			Step(ctx context.Context) bool
+		}).Step(ctx)
+	}
+
+	res, err := f.client.KarnessV1alpha1().Scenarios(metav1.NamespaceDefault).Get(ctx, "test", metav1.GetOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, v1alpha1.Complete, res.Status.State)
+	assert.Equal(t, "2 of 2", res.Status.Progress)
+}
+
+func TestCallbackAfterStatusUpdate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expect := `{"status":"done"}`
+
+	srv := httptest.NewUnstartedServer(nil)
+	callbacks := callbackexec.New("http://" + srv.Listener.Addr().String())
+	srv.Config.Handler = callbacks
+	srv.Start()
+
+	defer srv.Close()
+
+	f := newFixture(t)
+	f.harnessOpt = append(f.harnessOpt, harness.WithCallbacks(callbacks))
+
+	scena := newScenario("test", "", "", nil,
+		newEvent("callback",
+			v1alpha1.Action{
+				Name: "Wait-Done",
+				Callback: &action.Callback{
+					Variable: "CALLBACK_URL",
+					Timeout:  metav1.Duration{Duration: 5 * time.Second},
+				},
+			},
+			v1alpha1.Condition{
+				Response: &v1alpha1.ConditionResponse{Status: "OK", Body: v1alpha1.Body{JSON: &expect}},
+			},
+		),
+	)
+
+	f.scenarioList = append(f.scenarioList, scena)
+	f.objects = append(f.objects, scena)
+
+	c, _ := f.newController()
+	key := getKey(scena, t)
+
+	if !assert.NoError(t, c.syncHandler(ctx, key)) {
+		return
+	}
+
+	var e *callbackexec.Endpoint
+
+	assert.Eventually(t, func() bool {
+		var ok bool
+		e, ok = callbacks.Get(key, "CALLBACK_URL")
+
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	updated, err := f.client.KarnessV1alpha1().Scenarios(metav1.NamespaceDefault).Get(ctx, "test", metav1.GetOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// url sent to service before status update still works
+	informerUpdate(ctx, c, scena, updated)
+
+	res, err := http.Post(e.URL(), "application/json", strings.NewReader(expect))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	p, ok := c.harness.GetProcessor(key)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	p.(interface {
+		Step(ctx context.Context) bool
+	}).Step(ctx)
+
+	updated, err = f.client.KarnessV1alpha1().Scenarios(metav1.NamespaceDefault).Get(ctx, "test", metav1.GetOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, v1alpha1.Complete, updated.Status.State)
+}
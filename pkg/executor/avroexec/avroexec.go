@@ -0,0 +1,249 @@
+package avroexec
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+const (
+	// confluent wire format: magic byte and big endian schema id precede payload
+	magicByte  = 0
+	headerSize = 5
+
+	defaultTimeout = 10 * time.Second
+)
+
+var (
+	ErrNoSchema      = errors.New("payload doesn't reference schema and subject isn't set")
+	ErrSchemaMissing = errors.New("schema isn't found in registry")
+)
+
+type Option func(*Config)
+
+type Config struct {
+	client  *http.Client
+	subject string
+}
+
+// WithClient sets http client of schema registry
+func WithClient(c *http.Client) Option {
+	return func(cfg *Config) {
+		cfg.client = c
+	}
+}
+
+// WithSubject sets subject which latest schema decodes payloads without schema id
+func WithSubject(subject string) Option {
+	return func(cfg *Config) {
+		cfg.subject = subject
+	}
+}
+
+type service struct {
+	Config
+	registry string
+
+	mu     sync.Mutex
+	codecs map[int]*goavro.Codec
+	// missing ids aren't requested again
+	missing map[int]bool
+	// latests key: subject
+	latests map[string]*goavro.Codec
+}
+
+// New creates decoder which resolves schemas with schema registry compatible endpoint.
+// Schemas are cached by id and latest schema by subject for lifetime of decoder.
+func New(registry string, opt ...Option) *service {
+	c := Config{client: &http.Client{Timeout: defaultTimeout}}
+
+	for _, o := range opt {
+		o(&c)
+	}
+
+	return &service{
+		Config:   c,
+		registry: strings.TrimSuffix(registry, "/"),
+		codecs:   make(map[int]*goavro.Codec),
+		missing:  make(map[int]bool),
+		latests:  make(map[string]*goavro.Codec),
+	}
+}
+
+// Decode converts avro binary to avro JSON.
+// Payload in confluent wire format is decoded with referenced schema, other payloads with latest schema of subject.
+// Payload is taken as framed only when its schema id is found in registry,
+// avro binary of subject may start with zero byte too.
+func (s *service) Decode(ctx context.Context, data []byte) ([]byte, error) {
+	if len(data) >= headerSize && data[0] == magicByte {
+		codec, err := s.codec(ctx, int(binary.BigEndian.Uint32(data[1:headerSize])))
+
+		switch {
+		case err == nil:
+			return decode(codec, data[headerSize:])
+		case !errors.Is(err, ErrSchemaMissing) || s.subject == "":
+			return nil, err
+		}
+	}
+
+	if s.subject == "" {
+		return nil, ErrNoSchema
+	}
+
+	codec, err := s.latest(ctx, s.subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(codec, data)
+}
+
+func decode(codec *goavro.Codec, data []byte) ([]byte, error) {
+	native, _, err := codec.NativeFromBinary(data)
+	if err != nil {
+		return nil, fmt.Errorf("avro decode: %w", err)
+	}
+
+	return codec.TextualFromNative(nil, native)
+}
+
+type schemaResponse struct {
+	ID     int    `json:"id"`
+	Schema string `json:"schema"`
+}
+
+func (s *service) codec(ctx context.Context, id int) (*goavro.Codec, error) {
+	s.mu.Lock()
+	c, ok := s.codecs[id]
+	missing := s.missing[id]
+	s.mu.Unlock()
+
+	switch {
+	case ok:
+		return c, nil
+	case missing:
+		return nil, fmt.Errorf("avro schema %d: %w", id, ErrSchemaMissing)
+	}
+
+	var res schemaResponse
+	if err := s.get(ctx, "/schemas/ids/"+strconv.Itoa(id), &res); err != nil {
+		if errors.Is(err, ErrSchemaMissing) {
+			s.mu.Lock()
+			s.missing[id] = true
+			s.mu.Unlock()
+		}
+
+		return nil, err
+	}
+
+	res.ID = id
+
+	return s.store(res)
+}
+
+// latest schema of subject is resolved once, schemas registered later aren't used by decoder
+func (s *service) latest(ctx context.Context, subject string) (*goavro.Codec, error) {
+	s.mu.Lock()
+	c, ok := s.latests[subject]
+	s.mu.Unlock()
+
+	if ok {
+		return c, nil
+	}
+
+	var res schemaResponse
+	if err := s.get(ctx, "/subjects/"+url.PathEscape(subject)+"/versions/latest", &res); err != nil {
+		return nil, err
+	}
+
+	c, err := s.store(res)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.latests[subject] = c
+	s.mu.Unlock()
+
+	return c, nil
+}
+
+func (s *service) store(res schemaResponse) (*goavro.Codec, error) {
+	c, err := goavro.NewCodec(res.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("avro schema %d: %w", res.ID, err)
+	}
+
+	s.mu.Lock()
+	s.codecs[res.ID] = c
+	s.mu.Unlock()
+
+	return c, nil
+}
+
+func (s *service) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.registry+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("schema registry: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("schema registry %s: %w", path, ErrSchemaMissing)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("schema registry %s: %s", path, res.Status)
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("schema registry %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Decoders keeps decoders of scenario, so schemas are resolved once for all its actions
+type Decoders struct {
+	mu sync.Mutex
+	// key: registry and subject
+	decoders map[[2]string]*service
+}
+
+func NewDecoders() *Decoders {
+	return &Decoders{decoders: make(map[[2]string]*service)}
+}
+
+// Get returns decoder of registry and subject, it's created by the first call
+func (d *Decoders) Get(registry, subject string) *service {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := [2]string{registry, subject}
+
+	s, ok := d.decoders[key]
+	if !ok {
+		s = New(registry, WithSubject(subject))
+		d.decoders[key] = s
+	}
+
+	return s
+}
@@ -0,0 +1,114 @@
+package avroexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const userSchema = `{
+  "type": "record",
+  "name": "User",
+  "fields": [
+    {"name": "id", "type": "long"},
+    {"name": "name", "type": "string"}
+  ]
+}`
+
+func users(t *testing.T, users ...map[string]interface{}) [][]byte {
+	codec, err := goavro.NewCodec(userSchema)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	var res [][]byte
+
+	for _, u := range users {
+		payload, err := codec.BinaryFromNative(nil, u)
+		assert.NoError(t, err)
+
+		res = append(res, payload)
+	}
+
+	return res
+}
+
+func TestDecode(t *testing.T) {
+	payloads := users(t, map[string]interface{}{"id": int64(7), "name": "alice"}, map[string]interface{}{"id": int64(0), "name": "alice"})
+	if len(payloads) != 2 {
+		return
+	}
+
+	// zero id is encoded as zero byte, like magic byte of wire format
+	payload, zero := payloads[0], payloads[1]
+
+	srv := CreateMockRegistry(RegistryFixture{
+		Schemas:  map[int]string{1: userSchema},
+		Subjects: map[string]int{"users-value": 1},
+	})
+
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		opt    []Option
+		data   []byte
+		expect string
+	}{
+		{"schema id", nil, Frame(1, payload), `{"id":7,"name":"alice"}`},
+		{"subject", []Option{WithSubject("users-value")}, payload, `{"id":7,"name":"alice"}`},
+		{"subject zero byte", []Option{WithSubject("users-value")}, zero, `{"id":0,"name":"alice"}`},
+		{"no schema", nil, payload, ""},
+		{"zero byte without subject", nil, zero, ""},
+		{"unknown id", nil, Frame(2, payload), ""},
+		{"unknown subject", []Option{WithSubject("orders-value")}, payload, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := New(srv.URL, test.opt...).Decode(context.Background(), test.data)
+			if test.expect == "" {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expect, string(res))
+		})
+	}
+}
+
+func TestDecoders(t *testing.T) {
+	payloads := users(t, map[string]interface{}{"id": int64(7), "name": "alice"}, map[string]interface{}{"id": int64(0), "name": "alice"})
+	if len(payloads) != 2 {
+		return
+	}
+
+	data := [][]byte{Frame(1, payloads[0]), payloads[0], payloads[1]}
+
+	srv := CreateMockRegistry(RegistryFixture{
+		Schemas:  map[int]string{1: userSchema},
+		Subjects: map[string]int{"users-value": 1},
+	})
+
+	d := NewDecoders()
+	dec := d.Get(srv.URL, "users-value")
+
+	for _, b := range data {
+		_, err := dec.Decode(context.Background(), b)
+		assert.NoError(t, err)
+	}
+
+	// schemas of ids, missing ids and latest schema of subject aren't requested again
+	srv.Close()
+
+	assert.Same(t, dec, d.Get(srv.URL, "users-value"))
+	assert.NotSame(t, dec, d.Get(srv.URL, "orders-value"))
+
+	for _, b := range data {
+		_, err := dec.Decode(context.Background(), b)
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,51 @@
+package avroexec
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+)
+
+// RegistryFixture contains schemas served by mock registry
+type RegistryFixture struct {
+	// Schemas key: id
+	Schemas map[int]string
+	// Subjects key: subject, value: id of latest schema
+	Subjects map[string]int
+}
+
+// CreateMockRegistry serves schemas by id and latest schema of subjects
+func CreateMockRegistry(fx RegistryFixture) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := -1
+
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/schemas/ids/"):
+			id, _ = strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/schemas/ids/"))
+		case strings.HasPrefix(r.URL.Path, "/subjects/") && strings.HasSuffix(r.URL.Path, "/versions/latest"):
+			subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions/latest")
+			if v, ok := fx.Subjects[subject]; ok {
+				id = v
+			}
+		}
+
+		schema, ok := fx.Schemas[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(schemaResponse{ID: id, Schema: schema})
+	}))
+}
+
+// Frame prepends confluent wire format header to avro binary
+func Frame(id int, payload []byte) []byte {
+	res := make([]byte, headerSize, headerSize+len(payload))
+	binary.BigEndian.PutUint32(res[1:], uint32(id))
+
+	return append(res, payload...)
+}
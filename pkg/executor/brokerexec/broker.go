@@ -0,0 +1,108 @@
+package brokerexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	Kafka = "kafka"
+	NATS  = "nats"
+)
+
+var ErrUnknownKind = errors.New("unknown broker kind")
+
+// Message published to or consumed from broker
+type Message struct {
+	// Topic of kafka or subject of nats
+	Topic  string
+	Key    []byte
+	Value  []byte
+	Header map[string][]string
+}
+
+// Filter reports whether consumed message is expected one
+type Filter func(Message) bool
+
+// Decoder converts message value, e.g. protobuf or avro to JSON
+type Decoder func([]byte) ([]byte, error)
+
+type ConsumeRequest struct {
+	Topic string
+	// Decode is applied to message value before filter, message which can't be decoded fails consuming
+	Decode Decoder
+	// Filter nil accepts first message
+	Filter Filter
+	// FromBeginning reads retained messages of topic, by default only new messages are read.
+	// Ignored by brokers without persistence.
+	FromBeginning bool
+}
+
+// Broker hides differences of messaging systems
+type Broker interface {
+	Publish(ctx context.Context, m Message) error
+	// Subscribe starts receiving messages of topic, only FromBeginning of request is used
+	Subscribe(ctx context.Context, r ConsumeRequest) (Subscription, error)
+	// Consume subscribes and blocks until message accepted by filter arrives or ctx is done
+	Consume(ctx context.Context, r ConsumeRequest) (Message, error)
+	Close() error
+}
+
+// Subscription receives messages published since it was opened
+type Subscription interface {
+	// Next blocks until message accepted by filter of request arrives or ctx is done.
+	// Read messages aren't returned by following calls
+	Next(ctx context.Context, r ConsumeRequest) (Message, error)
+	Close() error
+}
+
+// consume reads the first accepted message of new subscription
+func consume(ctx context.Context, b Broker, r ConsumeRequest) (Message, error) {
+	sub, err := b.Subscribe(ctx, r)
+	if err != nil {
+		return Message{}, err
+	}
+
+	defer func() { _ = sub.Close() }()
+
+	return sub.Next(ctx, r)
+}
+
+// accept decodes message and checks filter, message which can't be decoded is error of consumer
+func (r ConsumeRequest) accept(m Message) (Message, bool, error) {
+	if r.Decode != nil {
+		v, err := r.Decode(m.Value)
+		if err != nil {
+			return m, false, fmt.Errorf("decode message of %s: %w", m.Topic, err)
+		}
+
+		m.Value = v
+	}
+
+	return m, r.Filter == nil || r.Filter(m), nil
+}
+
+// Open connects to broker of kind.
+// addr is comma separated list of kafka brokers or nats server url
+func Open(ctx context.Context, kind, addr string) (Broker, error) {
+	switch kind {
+	case Kafka:
+		return NewKafka(strings.Split(addr, ",")...), nil
+	case NATS:
+		return NewNATS(ctx, addr)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+}
+
+// Validate checks broker kind
+func Validate(kind string) error {
+	switch kind {
+	case Kafka, NATS:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+}
@@ -0,0 +1,304 @@
+package brokerexec
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const kafkaMaxWait = 500 * time.Millisecond
+
+type kafkaBroker struct {
+	brokers []string
+	writer  *kafka.Writer
+}
+
+// NewKafka doesn't connect until first call
+func NewKafka(brokers ...string) Broker {
+	return &kafkaBroker{
+		brokers: brokers,
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Balancer:               &kafka.Hash{},
+			AllowAutoTopicCreation: true,
+		},
+	}
+}
+
+func (k *kafkaBroker) Publish(ctx context.Context, m Message) error {
+	msg := kafka.Message{Topic: m.Topic, Key: m.Key, Value: m.Value}
+
+	for key, values := range m.Header {
+		for _, v := range values {
+			msg.Headers = append(msg.Headers, kafka.Header{Key: key, Value: []byte(v)})
+		}
+	}
+
+	if err := k.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("kafka publish: %w", err)
+	}
+
+	return nil
+}
+
+// Consume reads all partitions of topic without consumer group,
+// so offsets aren't committed and concurrent scenarios don't affect each other
+func (k *kafkaBroker) Consume(ctx context.Context, r ConsumeRequest) (Message, error) {
+	return consume(ctx, k, r)
+}
+
+// Subscribe remembers current end of partitions, or their beginning with FromBeginning
+func (k *kafkaBroker) Subscribe(ctx context.Context, r ConsumeRequest) (Subscription, error) {
+	partitions, err := k.partitions(ctx, r.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets, err := startOffsets(partitions, r.FromBeginning, func(p kafka.Partition) (int64, error) {
+		return lastOffset(ctx, r.Topic, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafkaSubscription{topic: r.Topic, open: k.openReader, offsets: offsets}, nil
+}
+
+// startOffsets of partitions, last is called for every partition unless fromBeginning
+func startOffsets(partitions []kafka.Partition, fromBeginning bool,
+	last func(kafka.Partition) (int64, error)) (map[int]int64, error) {
+	offsets := make(map[int]int64, len(partitions))
+
+	for _, p := range partitions {
+		if fromBeginning {
+			offsets[p.ID] = kafka.FirstOffset
+			continue
+		}
+
+		offset, err := last(p)
+		if err != nil {
+			return nil, err
+		}
+
+		offsets[p.ID] = offset
+	}
+
+	return offsets, nil
+}
+
+// lastOffset is offset of the next message of partition
+func lastOffset(ctx context.Context, topic string, p kafka.Partition) (int64, error) {
+	addr := net.JoinHostPort(p.Leader.Host, strconv.Itoa(p.Leader.Port))
+
+	conn, err := kafka.DialLeader(ctx, "tcp", addr, topic, p.ID)
+	if err != nil {
+		return 0, fmt.Errorf("kafka partition %d leader: %w", p.ID, err)
+	}
+
+	defer conn.Close()
+
+	offset, err := conn.ReadLastOffset()
+	if err != nil {
+		return 0, fmt.Errorf("kafka partition %d offset: %w", p.ID, err)
+	}
+
+	return offset, nil
+}
+
+// partitionReader reads single partition, *kafka.Reader in production
+type partitionReader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	Close() error
+}
+
+// openReader starts reading partition of topic at offset
+type openReader func(topic string, partition int, offset int64) (partitionReader, error)
+
+func (k *kafkaBroker) openReader(topic string, partition int, offset int64) (partitionReader, error) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   k.brokers,
+		Topic:     topic,
+		Partition: partition,
+		MaxWait:   kafkaMaxWait,
+	})
+
+	if err := reader.SetOffset(offset); err != nil {
+		_ = reader.Close()
+		return nil, fmt.Errorf("kafka partition %d offset: %w", partition, err)
+	}
+
+	return reader, nil
+}
+
+// kafkaSubscription reads partitions from offsets following the last read messages
+type kafkaSubscription struct {
+	topic string
+	open  openReader
+
+	mu sync.Mutex
+	// key: partition
+	offsets map[int]int64
+}
+
+func (s *kafkaSubscription) Next(ctx context.Context, r ConsumeRequest) (Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+		// key: partition, value: offset following handled message
+		handled   = make(map[int]int64)
+		delivered bool
+	)
+
+	// readers are stopped before offsets are moved
+	defer func() {
+		cancel()
+		wg.Wait()
+
+		for partition, offset := range handled {
+			s.offsets[partition] = offset
+		}
+	}()
+
+	handle := func(msg kafka.Message) {
+		mu.Lock()
+		handled[msg.Partition] = msg.Offset + 1
+		mu.Unlock()
+	}
+
+	// deliver handles only the first found message,
+	// others are left for next call even when found is already drained
+	deliver := func(msg kafka.Message) bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if delivered {
+			return false
+		}
+
+		delivered = true
+		handled[msg.Partition] = msg.Offset + 1
+
+		return true
+	}
+
+	found := make(chan Message, 1)
+	errs := make(chan error, len(s.offsets))
+
+	for partition, offset := range s.offsets {
+		reader, err := s.open(s.topic, partition, offset)
+		if err != nil {
+			return Message{}, err
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer reader.Close()
+
+			for {
+				msg, err := reader.ReadMessage(ctx)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				m, ok, err := r.accept(fromKafka(msg))
+				if err != nil {
+					// broken message isn't read again by next call
+					handle(msg)
+					errs <- err
+
+					return
+				}
+
+				if !ok {
+					handle(msg)
+					continue
+				}
+
+				if deliver(msg) {
+					found <- m
+				}
+
+				return
+			}
+		}()
+	}
+
+	var err error
+
+	select {
+	case m := <-found:
+		return m, nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case err = <-errs:
+	}
+
+	// message delivered meanwhile is already handled, so it's returned instead of being skipped
+	cancel()
+	wg.Wait()
+
+	select {
+	case m := <-found:
+		return m, nil
+	default:
+		return Message{}, fmt.Errorf("kafka consume %s: %w", s.topic, err)
+	}
+}
+
+func (s *kafkaSubscription) Close() error {
+	return nil
+}
+
+func (k *kafkaBroker) partitions(ctx context.Context, topic string) ([]kafka.Partition, error) {
+	var (
+		conn       *kafka.Conn
+		partitions []kafka.Partition
+		err        error
+	)
+
+	for _, addr := range k.brokers {
+		if conn, err = kafka.DialContext(ctx, "tcp", addr); err != nil {
+			continue
+		}
+
+		partitions, err = conn.ReadPartitions(topic)
+		_ = conn.Close()
+
+		if err == nil {
+			return partitions, nil
+		}
+	}
+
+	return nil, fmt.Errorf("kafka %s partitions: %w", topic, err)
+}
+
+func (k *kafkaBroker) Close() error {
+	return k.writer.Close()
+}
+
+func fromKafka(msg kafka.Message) Message {
+	m := Message{Topic: msg.Topic, Key: msg.Key, Value: msg.Value}
+
+	if len(msg.Headers) > 0 {
+		m.Header = make(map[string][]string, len(msg.Headers))
+
+		for _, h := range msg.Headers {
+			m.Header[h.Key] = append(m.Header[h.Key], string(h.Value))
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,231 @@
+package brokerexec
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestKafka requires local broker, e.g. KARNESS_TEST_KAFKA=localhost:9092
+func TestKafka(t *testing.T) {
+	addr := os.Getenv("KARNESS_TEST_KAFKA")
+	if addr == "" {
+		t.Skip("KARNESS_TEST_KAFKA is not set")
+	}
+
+	b, err := Open(context.Background(), Kafka, addr)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer b.Close()
+
+	topic := "karness-test-" + strings.ReplaceAll(time.Now().Format("150405.000"), ".", "")
+
+	// create topic
+	assert.NoError(t, b.Publish(context.Background(), Message{Topic: topic, Value: []byte(`{"id":0}`)}))
+
+	m, err := publishConsume(t, b, ConsumeRequest{
+		Topic:  topic,
+		Filter: func(m Message) bool { return string(m.Key) == "2" },
+	}, 10*time.Second, Message{Topic: topic, Key: []byte("2"), Value: []byte(`{"id":2}`),
+		Header: map[string][]string{"Trace": {"abc"}}})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":2}`, string(m.Value))
+	assert.Equal(t, []string{"abc"}, m.Header["Trace"])
+
+	m, err = publishConsume(t, b, ConsumeRequest{Topic: topic, FromBeginning: true}, 10*time.Second)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":0}`, string(m.Value))
+}
+
+// fakeTopic keeps partitions in memory, readers wait for appended messages like kafka.Reader does
+type fakeTopic struct {
+	mu sync.Mutex
+	// key: partition
+	messages map[int][]kafka.Message
+	appended chan struct{}
+}
+
+func newFakeTopic(partitions ...[]string) *fakeTopic {
+	f := &fakeTopic{messages: make(map[int][]kafka.Message), appended: make(chan struct{})}
+
+	for p, values := range partitions {
+		f.messages[p] = nil
+		f.append(p, values...)
+	}
+
+	return f
+}
+
+func (f *fakeTopic) append(partition int, values ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, v := range values {
+		offset := int64(len(f.messages[partition]))
+		f.messages[partition] = append(f.messages[partition], kafka.Message{
+			Topic: "orders", Partition: partition, Offset: offset, Value: []byte(v),
+		})
+	}
+
+	close(f.appended)
+	f.appended = make(chan struct{})
+}
+
+func (f *fakeTopic) subscription(offsets map[int]int64) *kafkaSubscription {
+	return &kafkaSubscription{topic: "orders", open: f.open, offsets: offsets}
+}
+
+func (f *fakeTopic) open(_ string, partition int, offset int64) (partitionReader, error) {
+	if offset == kafka.FirstOffset {
+		offset = 0
+	}
+
+	return &fakeReader{topic: f, partition: partition, offset: offset}, nil
+}
+
+type fakeReader struct {
+	topic     *fakeTopic
+	partition int
+	offset    int64
+}
+
+func (r *fakeReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	for {
+		r.topic.mu.Lock()
+		messages, appended := r.topic.messages[r.partition], r.topic.appended
+		r.topic.mu.Unlock()
+
+		if r.offset < int64(len(messages)) {
+			r.offset++
+			return messages[r.offset-1], nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case <-appended:
+		}
+	}
+}
+
+func (r *fakeReader) Close() error {
+	return nil
+}
+
+func next(s *kafkaSubscription, r ConsumeRequest) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	m, err := s.Next(ctx, r)
+
+	return string(m.Value), err
+}
+
+func TestStartOffsets(t *testing.T) {
+	partitions := []kafka.Partition{{ID: 0}, {ID: 1}}
+	last := func(p kafka.Partition) (int64, error) { return int64(10 + p.ID), nil }
+
+	offsets, err := startOffsets(partitions, false, last)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int64{0: 10, 1: 11}, offsets)
+
+	offsets, err = startOffsets(partitions, true, last)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int64{0: kafka.FirstOffset, 1: kafka.FirstOffset}, offsets)
+
+	errLeader := errors.New("no leader")
+
+	_, err = startOffsets(partitions, false, func(kafka.Partition) (int64, error) { return 0, errLeader })
+	assert.ErrorIs(t, err, errLeader)
+}
+
+func TestKafkaSubscriptionOffsets(t *testing.T) {
+	topic := newFakeTopic([]string{"a", "b"}, []string{"c"})
+	s := topic.subscription(map[int]int64{0: 1, 1: 1})
+
+	// messages before subscription are skipped
+	v, err := next(s, ConsumeRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "b", v)
+
+	_, err = next(s, ConsumeRequest{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	topic.append(1, "d")
+
+	v, err = next(s, ConsumeRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "d", v)
+	assert.Equal(t, map[int]int64{0: 2, 1: 2}, s.offsets)
+}
+
+func TestKafkaSubscriptionFromBeginning(t *testing.T) {
+	topic := newFakeTopic([]string{"a", "b", "c"})
+	s := topic.subscription(map[int]int64{0: kafka.FirstOffset})
+
+	v, err := next(s, ConsumeRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "a", v)
+
+	// filtered out message is consumed
+	v, err = next(s, ConsumeRequest{Filter: func(m Message) bool { return string(m.Value) == "c" }})
+	assert.NoError(t, err)
+	assert.Equal(t, "c", v)
+
+	_, err = next(s, ConsumeRequest{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestKafkaSubscriptionDecodeError(t *testing.T) {
+	topic := newFakeTopic([]string{"broken", "ok"})
+	s := topic.subscription(map[int]int64{0: 0})
+
+	errDecode := errors.New("decode")
+	r := ConsumeRequest{Decode: func(b []byte) ([]byte, error) {
+		if string(b) == "broken" {
+			return nil, errDecode
+		}
+
+		return b, nil
+	}}
+
+	_, err := next(s, r)
+	assert.ErrorIs(t, err, errDecode)
+
+	// broken message isn't read again
+	v, err := next(s, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", v)
+}
+
+func TestKafkaSubscriptionPartitions(t *testing.T) {
+	topic := newFakeTopic([]string{"a"}, []string{"b"}, []string{"c"})
+	s := topic.subscription(map[int]int64{0: 0, 1: 0, 2: 0})
+
+	// message found in one partition doesn't consume the others
+	var values []string
+
+	for i := 0; i < 3; i++ {
+		v, err := next(s, ConsumeRequest{})
+		assert.NoError(t, err)
+
+		values = append(values, v)
+	}
+
+	sort.Strings(values)
+	assert.Equal(t, []string{"a", "b", "c"}, values)
+
+	_, err := next(s, ConsumeRequest{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,198 @@
+package brokerexec
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NATSServer implements subset of nats client protocol: PUB/HPUB, SUB, UNSUB and PING.
+// It's enough for tests which don't require real server.
+type NATSServer struct {
+	l net.Listener
+
+	mu   sync.Mutex
+	subs map[*natsClient]map[string]string // client: sid: subject
+}
+
+type natsClient struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func (c *natsClient) send(format string, args ...interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, _ = fmt.Fprintf(c.w, format, args...)
+	_ = c.w.Flush()
+}
+
+// CreateNATSServer listens random port, nats url is returned by URL
+func CreateNATSServer() *NATSServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := &NATSServer{l: l, subs: make(map[*natsClient]map[string]string)}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *NATSServer) URL() string {
+	return "nats://" + s.l.Addr().String()
+}
+
+// Subscribed reports whether some client is subscribed to subject
+func (s *NATSServer) Subscribed(subject string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, subs := range s.subs {
+		for _, pattern := range subs {
+			if pattern == subject {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *NATSServer) Close() error {
+	return s.l.Close()
+}
+
+func (s *NATSServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	c := &natsClient{w: bufio.NewWriter(conn)}
+	r := bufio.NewReader(conn)
+
+	s.mu.Lock()
+	s.subs[c] = make(map[string]string)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subs, c)
+		s.mu.Unlock()
+	}()
+
+	addr := conn.LocalAddr().(*net.TCPAddr)
+	c.send("INFO {\"server_id\":\"mock\",\"version\":\"2.2.0\",\"host\":%q,\"port\":%d,\"headers\":true,\"max_payload\":1048576,\"proto\":1}\r\n",
+		addr.IP.String(), addr.Port)
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		args := strings.Fields(line)
+		if len(args) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			c.send("PONG\r\n")
+		case "SUB":
+			// SUB <subject> [queue group] <sid>
+			s.mu.Lock()
+			s.subs[c][args[len(args)-1]] = args[1]
+			s.mu.Unlock()
+		case "UNSUB":
+			s.mu.Lock()
+			delete(s.subs[c], args[1])
+			s.mu.Unlock()
+		case "PUB", "HPUB":
+			if err = s.publish(r, args); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// publish reads payload and delivers it to subscribers
+// PUB <subject> [reply-to] <#bytes>, HPUB <subject> [reply-to] <#header bytes> <#total bytes>
+func (s *NATSServer) publish(r *bufio.Reader, args []string) error {
+	headers := args[0] == "HPUB"
+
+	size, err := strconv.Atoi(args[len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	payload := make([]byte, size+2)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return err
+	}
+
+	subject := args[1]
+	reply := ""
+
+	sizes := args[len(args)-1:]
+	if headers {
+		sizes = args[len(args)-2:]
+	}
+
+	if len(args)-len(sizes) == 3 {
+		reply = args[2] + " "
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c, subs := range s.subs {
+		for sid, pattern := range subs {
+			if !subjectMatch(pattern, subject) {
+				continue
+			}
+
+			op := "MSG"
+			if headers {
+				op = "HMSG"
+			}
+
+			c.send("%s %s %s %s%s\r\n%s", op, subject, sid, reply, strings.Join(sizes, " "), payload)
+		}
+	}
+
+	return nil
+}
+
+// subjectMatch supports * and > wildcards
+func subjectMatch(pattern, subject string) bool {
+	p := strings.Split(pattern, ".")
+	t := strings.Split(subject, ".")
+
+	for i, token := range p {
+		if token == ">" {
+			return len(t) > i
+		}
+
+		if i >= len(t) || (token != "*" && token != t[i]) {
+			return false
+		}
+	}
+
+	return len(p) == len(t)
+}
@@ -0,0 +1,97 @@
+package brokerexec
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+type natsBroker struct {
+	conn *nats.Conn
+}
+
+func NewNATS(ctx context.Context, url string) (Broker, error) {
+	opt := []nats.Option{nats.Name("karness")}
+	if d, ok := ctx.Deadline(); ok {
+		opt = append(opt, nats.Timeout(time.Until(d)))
+	}
+
+	conn, err := nats.Connect(url, opt...)
+	if err != nil {
+		return nil, fmt.Errorf("nats connect: %w", err)
+	}
+
+	return &natsBroker{conn: conn}, nil
+}
+
+// Publish ignores Key, nats has no such concept
+func (n *natsBroker) Publish(ctx context.Context, m Message) error {
+	msg := &nats.Msg{Subject: m.Topic, Data: m.Value}
+	if len(m.Header) > 0 {
+		msg.Header = m.Header
+	}
+
+	if err := n.conn.PublishMsg(msg); err != nil {
+		return fmt.Errorf("nats publish: %w", err)
+	}
+
+	return n.flush(ctx)
+}
+
+// Consume subscribes to subject, FromBeginning is ignored: core nats doesn't retain messages
+func (n *natsBroker) Consume(ctx context.Context, r ConsumeRequest) (Message, error) {
+	return consume(ctx, n, r)
+}
+
+// Subscribe returns when server knows about subscription, FromBeginning is ignored
+func (n *natsBroker) Subscribe(ctx context.Context, r ConsumeRequest) (Subscription, error) {
+	sub, err := n.conn.SubscribeSync(r.Topic)
+	if err != nil {
+		return nil, fmt.Errorf("nats subscribe: %w", err)
+	}
+
+	if err = n.flush(ctx); err != nil {
+		_ = sub.Unsubscribe()
+		return nil, fmt.Errorf("nats subscribe: %w", err)
+	}
+
+	return &natsSubscription{sub: sub}, nil
+}
+
+type natsSubscription struct {
+	sub *nats.Subscription
+}
+
+func (s *natsSubscription) Next(ctx context.Context, r ConsumeRequest) (Message, error) {
+	for {
+		msg, err := s.sub.NextMsgWithContext(ctx)
+		if err != nil {
+			return Message{}, fmt.Errorf("nats consume %s: %w", s.sub.Subject, err)
+		}
+
+		m, ok, err := r.accept(Message{Topic: msg.Subject, Value: msg.Data, Header: msg.Header})
+		if err != nil || ok {
+			return m, err
+		}
+	}
+}
+
+func (s *natsSubscription) Close() error {
+	return s.sub.Unsubscribe()
+}
+
+// flush waits until server processed buffered commands
+func (n *natsBroker) flush(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return n.conn.Flush()
+	}
+
+	return n.conn.FlushWithContext(ctx)
+}
+
+func (n *natsBroker) Close() error {
+	n.conn.Close()
+	return nil
+}
@@ -0,0 +1,134 @@
+package brokerexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// publishConsume subscribes before messages are published, like consume action prepared at scenario start
+func publishConsume(t *testing.T, b Broker, r ConsumeRequest, timeout time.Duration, publish ...Message) (Message, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	sub, err := b.Subscribe(ctx, r)
+	if !assert.NoError(t, err) {
+		return Message{}, err
+	}
+
+	defer sub.Close()
+
+	for _, m := range publish {
+		assert.NoError(t, b.Publish(ctx, m))
+	}
+
+	return sub.Next(ctx, r)
+}
+
+func TestNATS(t *testing.T) {
+	srv := CreateNATSServer()
+	defer srv.Close()
+
+	b, err := Open(context.Background(), NATS, srv.URL())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer b.Close()
+
+	publish := []Message{
+		{Topic: "orders.created", Value: []byte(`{"id":1}`)},
+		{Topic: "orders.created", Value: []byte(`{"id":2}`), Header: map[string][]string{"Trace": {"abc"}}},
+	}
+
+	tests := []struct {
+		name   string
+		req    ConsumeRequest
+		expect string
+	}{
+		{"any", ConsumeRequest{Topic: "orders.created"}, ""},
+		{"wildcard", ConsumeRequest{Topic: "orders.>"}, ""},
+		{"filter", ConsumeRequest{Topic: "orders.created", Filter: func(m Message) bool {
+			return bytes.Equal(m.Value, []byte(`{"id":2}`))
+		}}, `{"id":2}`},
+		{"decode", ConsumeRequest{Topic: "orders.created", Decode: func(b []byte) ([]byte, error) {
+			return bytes.ReplaceAll(b, []byte("id"), []byte("order")), nil
+		}, Filter: func(m Message) bool {
+			return bytes.Equal(m.Value, []byte(`{"order":2}`))
+		}}, `{"order":2}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := publishConsume(t, b, test.req, time.Second, publish...)
+			assert.NoError(t, err)
+			assert.Equal(t, "orders.created", m.Topic)
+
+			if test.expect != "" {
+				assert.JSONEq(t, test.expect, string(m.Value))
+				assert.Equal(t, []string{"abc"}, m.Header["Trace"])
+			}
+		})
+	}
+
+	_, err = publishConsume(t, b, ConsumeRequest{Topic: "orders.created", Filter: func(Message) bool { return false }},
+		100*time.Millisecond, publish...)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = publishConsume(t, b, ConsumeRequest{Topic: "orders.created", Decode: func([]byte) ([]byte, error) {
+		return nil, errors.New("unexpected message")
+	}}, time.Second, publish...)
+	assert.EqualError(t, err, "decode message of orders.created: unexpected message")
+}
+
+func TestRegistry(t *testing.T) {
+	srv := CreateNATSServer()
+	defer srv.Close()
+
+	b, err := Open(context.Background(), NATS, srv.URL())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer b.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r := NewRegistry()
+	defer r.Close()
+
+	req := ConsumeRequest{Topic: "orders.created"}
+
+	sub, err := r.Subscribe(ctx, "default/test", "consume", NATS, srv.URL(), req)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// messages published before the first read aren't lost and aren't returned twice
+	for _, v := range []string{`{"id":1}`, `{"id":2}`} {
+		assert.NoError(t, b.Publish(ctx, Message{Topic: "orders.created", Value: []byte(v)}))
+	}
+
+	same, err := r.Subscribe(ctx, "default/test", "consume", NATS, srv.URL(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, sub, same)
+
+	for _, expect := range []string{`{"id":1}`, `{"id":2}`} {
+		m, err := same.Next(ctx, req)
+		assert.NoError(t, err)
+		assert.JSONEq(t, expect, string(m.Value))
+	}
+
+	r.Release("default/test")
+
+	_, ok := r.Get("default/test", "consume")
+	assert.False(t, ok)
+
+	_, err = sub.Next(ctx, req)
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package brokerexec
+
+import (
+	"context"
+	"sync"
+)
+
+// Registry keeps subscriptions opened when scenario starts,
+// so messages published in response to earlier events aren't missed by consume actions
+type Registry struct {
+	mu sync.Mutex
+	// key: owner, value: name: subscription
+	owners map[string]map[string]*subscription
+}
+
+type subscription struct {
+	Subscription
+	broker Broker
+}
+
+func NewRegistry() *Registry {
+	return &Registry{owners: make(map[string]map[string]*subscription)}
+}
+
+// Subscribe returns subscription of owner with given name, it's opened by the first call
+func (r *Registry) Subscribe(ctx context.Context, owner, name, kind, addr string, req ConsumeRequest) (Subscription, error) {
+	if s, ok := r.Get(owner, name); ok {
+		return s, nil
+	}
+
+	// connect outside of lock, it's blocking operation
+	b, err := Open(ctx, kind, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := b.Subscribe(ctx, req)
+	if err != nil {
+		_ = b.Close()
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// somebody was faster
+	if s, ok := r.owners[owner][name]; ok {
+		_ = sub.Close()
+		_ = b.Close()
+
+		return s, nil
+	}
+
+	if r.owners[owner] == nil {
+		r.owners[owner] = make(map[string]*subscription)
+	}
+
+	s := &subscription{Subscription: sub, broker: b}
+	r.owners[owner][name] = s
+
+	return s, nil
+}
+
+func (r *Registry) Get(owner, name string) (Subscription, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.owners[owner][name]
+	if !ok {
+		return nil, false
+	}
+
+	return s, true
+}
+
+// Release closes all subscriptions of owner
+func (r *Registry) Release(owner string) {
+	r.mu.Lock()
+	subs := r.owners[owner]
+	delete(r.owners, owner)
+	r.mu.Unlock()
+
+	for _, s := range subs {
+		s.close()
+	}
+}
+
+// Close releases subscriptions of all owners
+func (r *Registry) Close() {
+	r.mu.Lock()
+	owners := r.owners
+	r.owners = make(map[string]map[string]*subscription)
+	r.mu.Unlock()
+
+	for _, subs := range owners {
+		for _, s := range subs {
+			s.close()
+		}
+	}
+}
+
+func (s *subscription) close() {
+	_ = s.Subscription.Close()
+	_ = s.broker.Close()
+}
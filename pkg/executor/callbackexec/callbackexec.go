@@ -0,0 +1,190 @@
+package callbackexec
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// pathPrefix of callback endpoints
+	pathPrefix = "/callback/"
+
+	// maxBodySize of received request
+	maxBodySize = 1 << 20
+	// maxRequests kept by endpoint, the oldest not returned request is dropped
+	maxRequests = 100
+)
+
+// Request received by endpoint
+type Request struct {
+	Method string
+	Header http.Header
+	Body   []byte
+
+	consumed bool
+}
+
+// Matcher selects request which Wait returns
+type Matcher func(r Request) bool
+
+// Listener is http handler which serves endpoints registered by scenarios
+type Listener struct {
+	base string
+
+	mu sync.Mutex
+	// key: token
+	endpoints map[string]*Endpoint
+	// key: owner, value: name: endpoint
+	owners map[string]map[string]*Endpoint
+}
+
+// New listener, baseURL is address of listener reachable by services, e.g. http://karness.karness.svc:8090
+func New(baseURL string) *Listener {
+	return &Listener{
+		base:      strings.TrimSuffix(baseURL, "/"),
+		endpoints: make(map[string]*Endpoint),
+		owners:    make(map[string]map[string]*Endpoint),
+	}
+}
+
+// Register returns endpoint of owner with given name, the same endpoint is returned for repeated calls
+func (l *Listener) Register(owner, name string) *Endpoint {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.owners[owner][name]; ok {
+		return e
+	}
+
+	token := make([]byte, 16)
+	_, _ = rand.Read(token)
+
+	e := &Endpoint{
+		url:    l.base + pathPrefix + hex.EncodeToString(token),
+		max:    maxRequests,
+		notify: make(chan struct{}),
+	}
+
+	l.endpoints[hex.EncodeToString(token)] = e
+
+	if l.owners[owner] == nil {
+		l.owners[owner] = make(map[string]*Endpoint)
+	}
+
+	l.owners[owner][name] = e
+
+	return e
+}
+
+func (l *Listener) Get(owner, name string) (*Endpoint, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.owners[owner][name]
+
+	return e, ok
+}
+
+// Release removes all endpoints of owner
+func (l *Listener) Release(owner string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, e := range l.owners[owner] {
+		delete(l.endpoints, strings.TrimPrefix(e.url, l.base+pathPrefix))
+	}
+
+	delete(l.owners, owner)
+}
+
+func (l *Listener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mu.Lock()
+	e, ok := l.endpoints[strings.TrimPrefix(r.URL.Path, pathPrefix)]
+	l.mu.Unlock()
+
+	if !ok || !strings.HasPrefix(r.URL.Path, pathPrefix) {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	e.add(Request{Method: r.Method, Header: r.Header, Body: body})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Endpoint keeps received requests until they are returned by Wait
+type Endpoint struct {
+	url string
+	max int
+
+	mu   sync.Mutex
+	reqs []*Request
+	// notify is closed when request is received
+	notify chan struct{}
+}
+
+func (e *Endpoint) URL() string {
+	return e.url
+}
+
+func (e *Endpoint) add(r Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// returned requests aren't needed anymore
+	reqs := make([]*Request, 0, len(e.reqs)+1)
+
+	for _, req := range e.reqs {
+		if !req.consumed {
+			reqs = append(reqs, req)
+		}
+	}
+
+	reqs = append(reqs, &r)
+
+	if len(reqs) > e.max {
+		reqs = reqs[len(reqs)-e.max:]
+	}
+
+	e.reqs = reqs
+
+	close(e.notify)
+	e.notify = make(chan struct{})
+}
+
+// Wait returns the first received and not returned yet request which matches,
+// requests received before Wait call are checked too
+func (e *Endpoint) Wait(ctx context.Context, match Matcher) (Request, error) {
+	for {
+		e.mu.Lock()
+
+		for _, r := range e.reqs {
+			if !r.consumed && (match == nil || match(*r)) {
+				r.consumed = true
+				e.mu.Unlock()
+
+				return *r, nil
+			}
+		}
+
+		notify := e.notify
+		e.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Request{}, ctx.Err()
+		case <-notify:
+		}
+	}
+}
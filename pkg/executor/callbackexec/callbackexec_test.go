@@ -0,0 +1,112 @@
+package callbackexec
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func post(t *testing.T, url, body string) int {
+	res, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	if !assert.NoError(t, err) {
+		return 0
+	}
+
+	_ = res.Body.Close()
+
+	return res.StatusCode
+}
+
+func TestListener(t *testing.T) {
+	l := New("")
+
+	srv := httptest.NewServer(l)
+	defer srv.Close()
+
+	l.base = srv.URL
+
+	e := l.Register("default/test", "CALLBACK_URL")
+	assert.Equal(t, e, l.Register("default/test", "CALLBACK_URL"))
+	assert.True(t, strings.HasPrefix(e.URL(), srv.URL+pathPrefix))
+
+	// received before wait
+	assert.Equal(t, http.StatusOK, post(t, e.URL(), `{"status":"pending"}`))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		post(t, e.URL(), `{"status":"done"}`)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := func(r Request) bool { return strings.Contains(string(r.Body), "done") }
+
+	r, err := e.Wait(ctx, done)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, http.MethodPost, r.Method)
+	assert.Equal(t, `{"status":"done"}`, string(r.Body))
+	assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+	// request is returned once
+	r, err = e.Wait(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"status":"pending"}`, string(r.Body))
+
+	short, cancelShort := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelShort()
+
+	_, err = e.Wait(short, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	l.Release("default/test")
+
+	_, ok := l.Get("default/test", "CALLBACK_URL")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusNotFound, post(t, e.URL(), `{}`))
+}
+
+func TestEndpointLimits(t *testing.T) {
+	l := New("")
+
+	srv := httptest.NewServer(l)
+	defer srv.Close()
+
+	l.base = srv.URL
+
+	e := l.Register("default/test", "CALLBACK_URL")
+	e.max = 2
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(t, e.URL(), strings.Repeat("a", maxBodySize+1)))
+
+	for _, body := range []string{"1", "2", "3"} {
+		assert.Equal(t, http.StatusOK, post(t, e.URL(), body))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// the oldest request is dropped
+	for _, expect := range []string{"2", "3"} {
+		r, err := e.Wait(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, expect, string(r.Body))
+	}
+
+	// returned requests are dropped by the next one
+	assert.Equal(t, http.StatusOK, post(t, e.URL(), "4"))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	assert.Len(t, e.reqs, 1)
+}
@@ -0,0 +1,93 @@
+package graphqlexec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/d7561985/karness/pkg/executor/httpexec"
+)
+
+const (
+	// CodeOK is result code of response without errors
+	CodeOK = "OK"
+	// CodeErrors is result code of response which contains errors, data may be partial
+	CodeErrors = "ERRORS"
+)
+
+// Request is graphql operation sent with POST to Addr
+type Request struct {
+	Addr          string
+	Header        map[string]string
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+}
+
+type payload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response always contains both keys: data is null and errors is empty array when absent
+type Response struct {
+	Data   json.RawMessage   `json:"data"`
+	Errors []json.RawMessage `json:"errors"`
+}
+
+type service struct {
+	http interface {
+		Call(ctx context.Context, r httpexec.Request) (int, http.Header, []byte, error)
+	}
+}
+
+// New accepts options of http transport
+func New(opt ...httpexec.Option) *service {
+	return &service{http: httpexec.New(opt...)}
+}
+
+// Call performs operation and returns result code, response headers and body with data and errors split.
+// Response which isn't graphql one, for example error of gateway, is returned as is with http status as code.
+func (s *service) Call(ctx context.Context, r Request) (string, http.Header, []byte, error) {
+	body, err := json.Marshal(payload{Query: r.Query, OperationName: r.OperationName, Variables: r.Variables})
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("graphql request: %w", err)
+	}
+
+	status, header, res, err := s.http.Call(ctx, httpexec.Request{
+		Addr:   r.Addr,
+		Method: http.MethodPost,
+		Header: r.Header,
+		Body:   body,
+	})
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var resp Response
+	if err = json.Unmarshal(res, &resp); err != nil || (resp.Data == nil && resp.Errors == nil) {
+		return strconv.Itoa(status), header, res, nil
+	}
+
+	code := CodeOK
+	if len(resp.Errors) > 0 {
+		code = CodeErrors
+	}
+
+	if resp.Data == nil {
+		resp.Data = json.RawMessage("null")
+	}
+
+	if resp.Errors == nil {
+		resp.Errors = []json.RawMessage{}
+	}
+
+	if res, err = json.Marshal(resp); err != nil {
+		return "", nil, nil, fmt.Errorf("graphql response: %w", err)
+	}
+
+	return code, header, res, nil
+}
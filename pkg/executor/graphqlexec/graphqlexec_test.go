@@ -0,0 +1,87 @@
+package graphqlexec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCall(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		code     string
+		body     string
+	}{
+		{
+			name:     "data",
+			status:   http.StatusOK,
+			response: `{"data":{"user":{"id":"1"}}}`,
+			code:     CodeOK,
+			body:     `{"data":{"user":{"id":"1"}},"errors":[]}`,
+		},
+		{
+			name:     "partial",
+			status:   http.StatusOK,
+			response: `{"errors":[{"message":"denied","path":["user","email"]}],"data":{"user":null}}`,
+			code:     CodeErrors,
+			body:     `{"data":{"user":null},"errors":[{"message":"denied","path":["user","email"]}]}`,
+		},
+		{
+			name:     "errors only",
+			status:   http.StatusBadRequest,
+			response: `{"errors":[{"message":"syntax"}]}`,
+			code:     CodeErrors,
+			body:     `{"data":null,"errors":[{"message":"syntax"}]}`,
+		},
+		{
+			name:     "not graphql",
+			status:   http.StatusBadGateway,
+			response: `bad gateway`,
+			code:     "502",
+			body:     `bad gateway`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "token", r.Header.Get("Authorization"))
+
+				var p payload
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+				assert.Equal(t, payload{
+					Query:         `query User($id: ID!) { user(id: $id) { id } }`,
+					OperationName: "User",
+					Variables:     map[string]interface{}{"id": "1"},
+				}, p)
+
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.response))
+			}))
+
+			defer srv.Close()
+
+			code, _, body, err := New().Call(context.Background(), Request{
+				Addr:          srv.URL,
+				Header:        map[string]string{"Authorization": "token"},
+				Query:         `query User($id: ID!) { user(id: $id) { id } }`,
+				OperationName: "User",
+				Variables:     map[string]interface{}{"id": "1"},
+			})
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, test.code, code)
+			assert.Equal(t, test.body, string(body))
+		})
+	}
+}
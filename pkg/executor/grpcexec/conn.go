@@ -0,0 +1,195 @@
+package grpcexec
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const defaultIdleTimeout = 5 * time.Minute
+
+// WithManager makes service reuse connections of manager on behalf of owner
+// instead of dialing and closing connection on every call
+func WithManager(m *Manager, owner string) Option {
+	return func(c *Config) {
+		c.manager = m
+		c.owner = owner
+	}
+}
+
+// Manager caches client connections per address and credentials.
+// Connection is closed when nobody has used it during idle timeout
+// or when all its owners released it.
+type Manager struct {
+	idle time.Duration
+
+	mu    sync.Mutex
+	conns map[string]*managedConn
+
+	stop chan struct{}
+	once sync.Once
+}
+
+type managedConn struct {
+	cc     *grpc.ClientConn
+	owners map[string]struct{}
+	// inflight number of calls which haven't released connection yet
+	inflight int
+	used     time.Time
+}
+
+// NewManager starts idle connection collector which works until Close is called.
+// idle <= 0 means default idle timeout.
+func NewManager(idle time.Duration) *Manager {
+	if idle <= 0 {
+		idle = defaultIdleTimeout
+	}
+
+	m := &Manager{
+		idle:  idle,
+		conns: make(map[string]*managedConn),
+		stop:  make(chan struct{}),
+	}
+
+	go m.collect()
+
+	return m
+}
+
+// Get returns cached connection for key or dial new one.
+// Owner is marked as connection user, connection isn't closed as idle until release is called.
+func (m *Manager) Get(ctx context.Context, owner, key string, dial func(context.Context) (*grpc.ClientConn, error)) (*grpc.ClientConn, func(), error) {
+	if c, ok := m.lookup(owner, key); ok {
+		return c.cc, m.release(c), nil
+	}
+
+	// dial outside of lock, it's blocking operation
+	cc, err := dial(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// somebody was faster
+	if c, ok := m.conns[key]; ok {
+		_ = cc.Close()
+
+		m.use(owner, c)
+
+		return c.cc, m.release(c), nil
+	}
+
+	c := &managedConn{cc: cc, owners: make(map[string]struct{})}
+	m.use(owner, c)
+	m.conns[key] = c
+
+	return cc, m.release(c), nil
+}
+
+func (m *Manager) lookup(owner, key string) (*managedConn, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.conns[key]
+	if !ok {
+		return nil, false
+	}
+
+	m.use(owner, c)
+
+	return c, true
+}
+
+// use should be called under lock
+func (m *Manager) use(owner string, c *managedConn) {
+	c.owners[owner] = struct{}{}
+	c.inflight++
+	c.used = time.Now()
+}
+
+// release returns function which ends use of connection, idle timeout is counted since the last one
+func (m *Manager) release(c *managedConn) func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			c.inflight--
+			c.used = time.Now()
+		})
+	}
+}
+
+// Release removes owner from all connections and closes connections nobody owns anymore
+func (m *Manager) Release(owner string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, c := range m.conns {
+		if _, ok := c.owners[owner]; !ok {
+			continue
+		}
+
+		delete(c.owners, owner)
+
+		if len(c.owners) == 0 {
+			_ = c.cc.Close()
+			delete(m.conns, key)
+		}
+	}
+}
+
+// Len returns number of opened connections
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.conns)
+}
+
+// Close stops idle collector and closes all connections
+func (m *Manager) Close() {
+	m.once.Do(func() {
+		close(m.stop)
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, c := range m.conns {
+		_ = c.cc.Close()
+		delete(m.conns, key)
+	}
+}
+
+func (m *Manager) collect() {
+	t := time.NewTicker(m.idle / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-t.C:
+			m.closeIdle()
+		}
+	}
+}
+
+func (m *Manager) closeIdle() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, c := range m.conns {
+		if c.inflight == 0 && time.Since(c.used) >= m.idle {
+			_ = c.cc.Close()
+			delete(m.conns, key)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package grpcexec
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	pb "google.golang.org/grpc/examples/helloworld/helloworld"
+)
+
+func TestManager(t *testing.T) {
+	l, srv := CreateMockServer(Fixture{
+		Res: &pb.HelloReply{Message: "OK"},
+		CB:  func(req *pb.HelloRequest) {},
+	})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	m := NewManager(time.Minute)
+	defer m.Close()
+
+	path := Path{
+		Package: "helloworld",
+		Service: "Greeter",
+		RPC:     "SayHello",
+	}
+
+	for _, owner := range []string{"A", "A", "B"} {
+		c, _, err := New(WithManager(m, owner)).Call(context.Background(), l.Addr().String(), path, `{}`)
+		assert.NoError(t, err)
+		assert.Equal(t, codes.OK, c)
+	}
+
+	// same address and credentials share connection
+	assert.Equal(t, 1, m.Len())
+
+	cc, release, err := m.Get(context.Background(), "A", New().connKey(l.Addr().String()), nil)
+	assert.NoError(t, err)
+
+	release()
+
+	m.Release("A")
+	assert.Equal(t, 1, m.Len(), "B still owns connection")
+
+	m.Release("B")
+	assert.Equal(t, 0, m.Len())
+	assert.Equal(t, connectivity.Shutdown, cc.GetState())
+}
+
+func TestManagerIdle(t *testing.T) {
+	l, srv := CreateMockServer(Fixture{
+		Res: &pb.HelloReply{Message: "OK"},
+		CB:  func(req *pb.HelloRequest) {},
+	})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	m := NewManager(50 * time.Millisecond)
+	defer m.Close()
+
+	path := Path{
+		Package: "helloworld",
+		Service: "Greeter",
+		RPC:     "SayHello",
+	}
+
+	_, _, err := New(WithManager(m, "A")).Call(context.Background(), l.Addr().String(), path, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, m.Len())
+
+	assert.Eventually(t, func() bool { return m.Len() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestManagerInUse(t *testing.T) {
+	l, srv := CreateMockServer(Fixture{
+		Res: &pb.HelloReply{Message: "OK"},
+		CB:  func(req *pb.HelloRequest) {},
+	})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	m := NewManager(50 * time.Millisecond)
+	defer m.Close()
+
+	addr := l.Addr().String()
+
+	cc, release, err := m.Get(context.Background(), "A", New().connKey(addr), func(ctx context.Context) (*grpc.ClientConn, error) {
+		return New().dial(ctx, addr)
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// long call keeps connection open after idle timeout
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 1, m.Len())
+	assert.NotEqual(t, connectivity.Shutdown, cc.GetState())
+
+	release()
+	release()
+
+	assert.Eventually(t, func() bool { return m.Len() == 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, connectivity.Shutdown, cc.GetState())
+}
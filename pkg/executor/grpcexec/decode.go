@@ -0,0 +1,61 @@
+package grpcexec
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fullstorydev/grpcurl"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+)
+
+var ErrNoDescriptors = errors.New("descriptor source is required")
+
+// Decoder resolves messageType once and returns function which converts protobuf wire format encoded messages to JSON.
+// Only file descriptor sources are used: WithProtoset, WithProtosetContent, WithProtoFiles, WithProtoContent
+func (g *service) Decoder(messageType string) (func(data []byte) ([]byte, error), error) {
+	if !g.hasFileSource() {
+		return nil, ErrNoDescriptors
+	}
+
+	src, err := g.fileSource()
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := src.FindSymbol(messageType)
+	if err != nil {
+		return nil, fmt.Errorf("message type %q: %w", messageType, err)
+	}
+
+	md, ok := d.(*desc.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not message type", messageType)
+	}
+
+	formatter := grpcurl.NewJSONFormatter(g.emitDefaults, grpcurl.AnyResolverFromDescriptorSource(src))
+
+	return func(data []byte) ([]byte, error) {
+		msg := dynamic.NewMessage(md)
+		if err := msg.Unmarshal(data); err != nil {
+			return nil, fmt.Errorf("decode %q: %w", messageType, err)
+		}
+
+		res, err := formatter(msg)
+		if err != nil {
+			return nil, fmt.Errorf("format %q: %w", messageType, err)
+		}
+
+		return []byte(res), nil
+	}, nil
+}
+
+// Decode converts single message, use Decoder for many messages of the same type
+func (g *service) Decode(messageType string, data []byte) ([]byte, error) {
+	decode, err := g.Decoder(messageType)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(data)
+}
@@ -0,0 +1,42 @@
+package grpcexec
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	pb "google.golang.org/grpc/examples/helloworld/helloworld"
+)
+
+func TestDecode(t *testing.T) {
+	data, err := proto.Marshal(&pb.HelloReply{Message: "OK"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name string
+		opt  []Option
+		typ  string
+		err  bool
+	}{
+		{"protoset", []Option{WithProtosetContent(helloworldProtoset(t))}, "helloworld.HelloReply", false},
+		{"proto", []Option{WithProtoContent(map[string]string{"helloworld.proto": helloworldProto})}, "helloworld.HelloReply", false},
+		{"unknown type", []Option{WithProtosetContent(helloworldProtoset(t))}, "helloworld.Unknown", true},
+		{"service", []Option{WithProtosetContent(helloworldProtoset(t))}, "helloworld.Greeter", true},
+		{"no descriptors", nil, "helloworld.HelloReply", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := New(test.opt...).Decode(test.typ, data)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"message":"OK"}`, string(res))
+		})
+	}
+}
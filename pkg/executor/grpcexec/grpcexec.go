@@ -36,6 +36,9 @@ type Config struct {
 	cacert         string
 	cert           string
 	key            string
+	cacertPEM      []byte
+	certPEM        []byte
+	keyPEM         []byte
 	userAgent      string
 	plaintext      bool
 	insecure       bool
@@ -47,6 +50,19 @@ type Config struct {
 	format             grpcurl.Format
 
 	formatError bool
+
+	// descriptor sources used along with server reflection
+	protoset        []string
+	protosetContent [][]byte
+	importPaths     []string
+	protoFiles      []string
+	protoContent    map[string]string
+	// files is already parsed descriptor source, it replaces sources above
+	files grpcurl.DescriptorSource
+
+	// manager shares connections between calls, owner is the one on whose behalf calls are made
+	manager *Manager
+	owner   string
 }
 
 type service struct {
@@ -70,39 +86,46 @@ func New(opt ...Option) *service {
 	return &service{Config: *g}
 }
 
+// parserFunc constructs request supplier and response formatter for particular descriptor source
+type parserFunc func(grpcurl.DescriptorSource, grpcurl.FormatOptions) (grpcurl.RequestParser, grpcurl.Formatter, error)
+
 // @symbol: {package}.{service}/{rpc}
 // @request - json with request
 func (g *service) Call(ctx context.Context, addr string, symbol Path, request string) (codes.Code, []byte, error) {
-	cc, err := g.dial(ctx, addr)
+	return g.invoke(ctx, addr, symbol, func(src grpcurl.DescriptorSource, opts grpcurl.FormatOptions) (grpcurl.RequestParser, grpcurl.Formatter, error) {
+		return grpcurl.RequestParserAndFormatter(g.format, src, bytes.NewBufferString(request), opts)
+	})
+}
+
+// CallBinary same as Call but request is protobuf wire format encoded message
+func (g *service) CallBinary(ctx context.Context, addr string, symbol Path, request []byte) (codes.Code, []byte, error) {
+	return g.invoke(ctx, addr, symbol, func(src grpcurl.DescriptorSource, opts grpcurl.FormatOptions) (grpcurl.RequestParser, grpcurl.Formatter, error) {
+		// parser is useless here, we need formatter only
+		_, formatter, err := grpcurl.RequestParserAndFormatter(g.format, src, bytes.NewReader(nil), opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &binaryParser{data: request}, formatter, nil
+	})
+}
+
+func (g *service) invoke(ctx context.Context, addr string, symbol Path, parser parserFunc) (codes.Code, []byte, error) {
+	cc, release, err := g.conn(ctx, addr)
 	if err != nil {
 		return 0, nil, fmt.Errorf("call error: %w", err)
 	}
 
-	md := grpcurl.MetadataFromHeaders(nil)
-	refCtx := metadata.NewOutgoingContext(ctx, md)
+	defer release()
 
-	refClient := grpcreflect.NewClient(refCtx, reflectpb.NewServerReflectionClient(cc))
-	reflSource := grpcurl.DescriptorSourceFromServer(ctx, refClient)
-
-	//if fileSource != nil {
-	//	descSource = compositeSource{reflSource, fileSource}
-	//} else {
-	//	descSource = reflSource
-	//}
-	descSource := reflSource
-
-	// if not verbose output, then also include record delimiters
-	// between each message, so output could potentially be piped
-	// to another grpcurl process
-	includeSeparators := g.verbosityLevel == 0
-	options := grpcurl.FormatOptions{
-		EmitJSONDefaultFields: g.emitDefaults,
-		IncludeTextSeparator:  includeSeparators,
-		AllowUnknownFields:    g.allowUnknownFields,
+	descSource, err := g.descriptorSource(ctx, cc)
+	if err != nil {
+		return 0, nil, fmt.Errorf("descriptor source error: %w", err)
 	}
 
-	in := bytes.NewBufferString(request)
-	rf, formatter, err := grpcurl.RequestParserAndFormatter(g.format, descSource, in, options)
+	options := g.formatOptions()
+
+	rf, formatter, err := parser(descSource, options)
 	if err != nil {
 		return 0, nil, fmt.Errorf("failed to construct request parser and formatter for %q: %w", g.format, err)
 	}
@@ -152,6 +175,60 @@ func (g *service) Call(ctx context.Context, addr string, symbol Path, request st
 	return h.Status.Code(), buf.Bytes(), nil
 }
 
+// conn returns managed connection or dial new one, release function should be called when connection isn't used anymore
+func (g *Config) conn(ctx context.Context, addr string) (cc *grpc.ClientConn, release func(), err error) {
+	if g.manager == nil {
+		if cc, err = g.dial(ctx, addr); err != nil {
+			return nil, nil, err
+		}
+
+		return cc, func() { _ = cc.Close() }, nil
+	}
+
+	return g.manager.Get(ctx, g.owner, g.connKey(addr), func(ctx context.Context) (*grpc.ClientConn, error) {
+		return g.dial(ctx, addr)
+	})
+}
+
+// connKey identifies connection by address and all dial options
+func (g *Config) connKey(addr string) string {
+	return fmt.Sprintf("%s|%v|%v|%s|%s|%s|%s|%s|%s|%s|%v|%v|%d",
+		addr, g.plaintext, g.insecure, g.cacert, g.cert, g.key, g.tlsHash(), g.serverName, g.authority,
+		g.userAgent, g.connectTimeout, g.keepaliveTime, g.maxMsgSz)
+}
+
+func (g *Config) descriptorSource(ctx context.Context, cc *grpc.ClientConn) (grpcurl.DescriptorSource, error) {
+	md := grpcurl.MetadataFromHeaders(nil)
+	refCtx := metadata.NewOutgoingContext(ctx, md)
+
+	refClient := grpcreflect.NewClient(refCtx, reflectpb.NewServerReflectionClient(cc))
+	reflSource := grpcurl.DescriptorSourceFromServer(ctx, refClient)
+
+	if !g.hasFileSource() {
+		return reflSource, nil
+	}
+
+	fileSource, err := g.fileSource()
+	if err != nil {
+		return nil, err
+	}
+
+	return compositeSource{reflection: reflSource, file: fileSource}, nil
+}
+
+func (g *Config) formatOptions() grpcurl.FormatOptions {
+	// if not verbose output, then also include record delimiters
+	// between each message, so output could potentially be piped
+	// to another grpcurl process
+	includeSeparators := g.verbosityLevel == 0
+
+	return grpcurl.FormatOptions{
+		EmitJSONDefaultFields: g.emitDefaults,
+		IncludeTextSeparator:  includeSeparators,
+		AllowUnknownFields:    g.allowUnknownFields,
+	}
+}
+
 func (g *Config) dial(ctx context.Context, addr string) (*grpc.ClientConn, error) {
 	dialTime := 10 * time.Second
 	if g.connectTimeout > 0 {
@@ -178,7 +255,7 @@ func (g *Config) dial(ctx context.Context, addr string) (*grpc.ClientConn, error
 	if !g.plaintext {
 		var err error
 
-		creds, err = grpcurl.ClientTransportCredentials(g.insecure, g.cacert, g.cert, g.key)
+		creds, err = g.transportCredentials()
 		if err != nil {
 			return nil, fmt.Errorf("failed to configure transport credentials: %w", err)
 		}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
 	pb "google.golang.org/grpc/examples/helloworld/helloworld"
@@ -47,3 +48,34 @@ func TestGRPC(t *testing.T) {
 
 	assert.Equal(t, desire, out)
 }
+
+func TestGRPCBinary(t *testing.T) {
+	name := "TEST_NAME"
+
+	l, srv := CreateMockServer(Fixture{
+		Res: &pb.HelloReply{Message: "OK"},
+		CB: func(req *pb.HelloRequest) {
+			assert.Equal(t, name, req.Name)
+		},
+	})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	req, err := proto.Marshal(&pb.HelloRequest{Name: name})
+	assert.NoError(t, err)
+
+	path := Path{
+		Package: "helloworld",
+		Service: "Greeter",
+		RPC:     "SayHello",
+	}
+
+	c, body, err := New().CallBinary(context.Background(), l.Addr().String(), path, req)
+	assert.NoError(t, err)
+	assert.Equal(t, codes.OK, c)
+
+	out := make(map[string]string)
+	assert.NoError(t, json.Unmarshal(body, &out))
+	assert.Equal(t, map[string]string{"message": "OK"}, out)
+}
@@ -0,0 +1,107 @@
+package grpcexec
+
+import (
+	"context"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const defaultHealthInterval = time.Second
+
+// HealthRequest of grpc.health.v1.Health
+type HealthRequest struct {
+	// Service name, empty checks server overall
+	Service string
+	// Watch uses Health/Watch stream instead of polling Health/Check
+	Watch bool
+	// Interval between checks and reconnects, 1s by default
+	Interval time.Duration
+}
+
+// WaitServing waits until service is SERVING or ctx is done.
+// Dial and rpc errors are retried as target may be not started yet.
+// Status reported by the last answered check and the last error are returned when ctx is done.
+func (g *service) WaitServing(ctx context.Context, addr string, r HealthRequest) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+
+	check := g.check
+	if r.Watch {
+		check = g.watch
+	}
+
+	// failed check knows nothing about status, e.g. when it's interrupted by ctx
+	last := healthpb.HealthCheckResponse_UNKNOWN
+
+	for {
+		status, err := check(ctx, addr, r.Service)
+		if err == nil && status == healthpb.HealthCheckResponse_SERVING {
+			return status, nil
+		}
+
+		// broken watch stream returns status it received before
+		if err == nil || status != healthpb.HealthCheckResponse_UNKNOWN {
+			last = status
+		}
+
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				err = ctx.Err()
+			}
+
+			return last, err
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (g *service) check(ctx context.Context, addr, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	cc, release, err := g.conn(ctx, addr)
+	if err != nil {
+		return healthpb.HealthCheckResponse_UNKNOWN, err
+	}
+
+	defer release()
+
+	res, err := healthpb.NewHealthClient(cc).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return healthpb.HealthCheckResponse_UNKNOWN, err
+	}
+
+	return res.GetStatus(), nil
+}
+
+// watch returns when service becomes SERVING or stream is broken
+func (g *service) watch(ctx context.Context, addr, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	cc, release, err := g.conn(ctx, addr)
+	if err != nil {
+		return healthpb.HealthCheckResponse_UNKNOWN, err
+	}
+
+	defer release()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := healthpb.NewHealthClient(cc).Watch(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return healthpb.HealthCheckResponse_UNKNOWN, err
+	}
+
+	status := healthpb.HealthCheckResponse_UNKNOWN
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return status, err
+		}
+
+		if status = res.GetStatus(); status == healthpb.HealthCheckResponse_SERVING {
+			return status, nil
+		}
+	}
+}
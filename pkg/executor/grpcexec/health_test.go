@@ -0,0 +1,57 @@
+package grpcexec
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestWaitServing(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     HealthRequest
+		serve   bool
+		timeout time.Duration
+		status  healthpb.HealthCheckResponse_ServingStatus
+		err     bool
+	}{
+		{"check", HealthRequest{Service: "orders", Interval: 20 * time.Millisecond}, true, 5 * time.Second, healthpb.HealthCheckResponse_SERVING, false},
+		{"watch", HealthRequest{Service: "orders", Watch: true}, true, 5 * time.Second, healthpb.HealthCheckResponse_SERVING, false},
+		{"timeout", HealthRequest{Service: "orders", Interval: 20 * time.Millisecond}, false, 200 * time.Millisecond, healthpb.HealthCheckResponse_NOT_SERVING, true},
+		{"watch timeout", HealthRequest{Service: "orders", Watch: true, Interval: 20 * time.Millisecond}, false, 200 * time.Millisecond, healthpb.HealthCheckResponse_NOT_SERVING, true},
+		{"unknown service", HealthRequest{Service: "unknown", Interval: 20 * time.Millisecond}, true, 200 * time.Millisecond, healthpb.HealthCheckResponse_UNKNOWN, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, s, h := CreateHealthServer()
+			defer s.Stop()
+
+			h.SetServingStatus("orders", healthpb.HealthCheckResponse_NOT_SERVING)
+
+			if test.serve {
+				go func() {
+					time.Sleep(100 * time.Millisecond)
+					h.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+				}()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), test.timeout)
+			defer cancel()
+
+			status, err := New().WaitServing(ctx, l.Addr().String(), test.req)
+
+			assert.Equal(t, test.status, status)
+
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
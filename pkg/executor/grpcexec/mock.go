@@ -2,10 +2,12 @@ package grpcexec
 
 import (
 	"context"
+	"crypto/tls"
 	"log"
 	"net"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/examples/helloworld/helloworld"
 	"google.golang.org/grpc/reflection"
 )
@@ -15,6 +17,12 @@ type Fixture struct {
 	Res *helloworld.HelloReply
 
 	CB func(*helloworld.HelloRequest)
+
+	// NoReflection disables server reflection
+	NoReflection bool
+
+	// TLS turns on secure server
+	TLS *tls.Config
 }
 
 type MockServer struct {
@@ -33,10 +41,16 @@ func CreateMockServer(fx Fixture) (net.Listener, *grpc.Server) {
 		log.Fatal(err)
 	}
 
-	s := grpc.NewServer()
+	var opts []grpc.ServerOption
+	if fx.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(fx.TLS)))
+	}
 
-	// Enable reflection
-	reflection.Register(s)
+	s := grpc.NewServer(opts...)
+
+	if !fx.NoReflection {
+		reflection.Register(s)
+	}
 
 	helloworld.RegisterGreeterServer(s, &MockServer{Fixture: fx})
 
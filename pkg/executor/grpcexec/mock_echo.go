@@ -0,0 +1,106 @@
+package grpcexec
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/examples/features/proto/echo"
+	"google.golang.org/grpc/reflection"
+)
+
+// EchoFixture configure streaming behaviour of EchoServer
+type EchoFixture struct {
+	// Repeat number of server stream responses, 0 means until client gone
+	Repeat int
+	// Interval between server stream responses
+	Interval time.Duration
+}
+
+// EchoServer implements all kind of rpc:
+// unary and bidi echo request, server stream repeats request,
+// client stream returns all received messages joined by space
+type EchoServer struct {
+	echo.UnimplementedEchoServer
+	EchoFixture
+}
+
+func (s *EchoServer) UnaryEcho(_ context.Context, req *echo.EchoRequest) (*echo.EchoResponse, error) {
+	return &echo.EchoResponse{Message: req.Message}, nil
+}
+
+func (s *EchoServer) ServerStreamingEcho(req *echo.EchoRequest, stream echo.Echo_ServerStreamingEchoServer) error {
+	for i := 0; s.Repeat == 0 || i < s.Repeat; i++ {
+		if err := stream.Send(&echo.EchoResponse{Message: req.Message}); err != nil {
+			return err
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-time.After(s.Interval):
+		}
+	}
+
+	return nil
+}
+
+func (s *EchoServer) ClientStreamingEcho(stream echo.Echo_ClientStreamingEchoServer) error {
+	var res []string
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&echo.EchoResponse{Message: strings.Join(res, " ")})
+		}
+
+		if err != nil {
+			return err
+		}
+
+		res = append(res, req.Message)
+	}
+}
+
+func (s *EchoServer) BidirectionalStreamingEcho(stream echo.Echo_BidirectionalStreamingEchoServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err = stream.Send(&echo.EchoResponse{Message: req.Message}); err != nil {
+			return err
+		}
+	}
+}
+
+func CreateEchoServer(fx EchoFixture) (net.Listener, *grpc.Server) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := grpc.NewServer()
+
+	// Enable reflection
+	reflection.Register(s)
+
+	echo.RegisterEchoServer(s, &EchoServer{EchoFixture: fx})
+
+	go func() {
+		if err := s.Serve(l); err != nil {
+			log.Fatalf("Server exited with error: %v", err)
+		}
+	}()
+
+	return l, s
+}
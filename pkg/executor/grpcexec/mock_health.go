@@ -0,0 +1,31 @@
+package grpcexec
+
+import (
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// CreateHealthServer serves grpc.health.v1.Health, statuses are changed with returned health server
+func CreateHealthServer() (net.Listener, *grpc.Server, *health.Server) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := grpc.NewServer()
+	h := health.NewServer()
+
+	healthpb.RegisterHealthServer(s, h)
+
+	go func() {
+		if err := s.Serve(l); err != nil {
+			log.Printf("health server exited with error: %v", err)
+		}
+	}()
+
+	return l, s, h
+}
@@ -0,0 +1,116 @@
+package grpcexec
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+// TLSServerName is the name server certificate of TLSFixture is issued for
+const TLSServerName = "localhost"
+
+// TLSFixture contains self-signed CA with server and client certificates issued by it
+type TLSFixture struct {
+	// PEM encoded CA certificate, client certificate and key
+	CA         []byte
+	ClientCert []byte
+	ClientKey  []byte
+
+	// Server config requires and verifies client certificate
+	Server *tls.Config
+}
+
+func CreateTLSFixture() (*TLSFixture, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	caTpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "karness-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTpl, caTpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte, err error) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tpl := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: TLSServerName},
+			DNSNames:     []string{TLSServerName},
+			IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, tpl, ca, &key.PublicKey, caKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+	}
+
+	srvCert, srvKey, err := issue(2, x509.ExtKeyUsageServerAuth)
+	if err != nil {
+		return nil, fmt.Errorf("issue server certificate: %w", err)
+	}
+
+	clientCert, clientKey, err := issue(3, x509.ExtKeyUsageClientAuth)
+	if err != nil {
+		return nil, fmt.Errorf("issue client certificate: %w", err)
+	}
+
+	srvPair, err := tls.X509KeyPair(srvCert, srvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	return &TLSFixture{
+		CA:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		ClientCert: clientCert,
+		ClientKey:  clientKey,
+		Server: &tls.Config{
+			Certificates: []tls.Certificate{srvPair},
+			ClientCAs:    pool,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+		},
+	}, nil
+}
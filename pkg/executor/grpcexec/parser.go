@@ -0,0 +1,27 @@
+package grpcexec
+
+import (
+	"io"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// binaryParser supplies single request message encoded in protobuf wire format
+type binaryParser struct {
+	data []byte
+	num  int
+}
+
+func (b *binaryParser) Next(msg proto.Message) error {
+	if b.num > 0 {
+		return io.EOF
+	}
+
+	b.num++
+
+	return proto.Unmarshal(b.data, msg)
+}
+
+func (b *binaryParser) NumRequests() int {
+	return b.num
+}
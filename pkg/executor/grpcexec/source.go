@@ -0,0 +1,246 @@
+package grpcexec
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fullstorydev/grpcurl"
+	"github.com/golang/protobuf/proto"
+	descpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+)
+
+// WithProtoset adds compiled descriptor set files (protoc --descriptor_set_out) as descriptor source
+func WithProtoset(files ...string) Option {
+	return func(c *Config) {
+		c.protoset = append(c.protoset, files...)
+	}
+}
+
+// WithProtosetContent adds in-memory compiled descriptor sets as descriptor source
+func WithProtosetContent(sets ...[]byte) Option {
+	return func(c *Config) {
+		c.protosetContent = append(c.protosetContent, sets...)
+	}
+}
+
+// WithProtoFiles adds .proto sources as descriptor source,
+// files and their imports are resolved relative to importPaths
+func WithProtoFiles(importPaths []string, files ...string) Option {
+	return func(c *Config) {
+		c.importPaths = append(c.importPaths, importPaths...)
+		c.protoFiles = append(c.protoFiles, files...)
+	}
+}
+
+// WithProtoContent adds in-memory .proto sources as descriptor source
+// key: file name, value: file content
+func WithProtoContent(files map[string]string) Option {
+	return func(c *Config) {
+		if c.protoContent == nil {
+			c.protoContent = make(map[string]string)
+		}
+
+		for name, content := range files {
+			c.protoContent[name] = content
+		}
+	}
+}
+
+// WithDescriptorSource uses source returned by Descriptors instead of parsing files on every call
+func WithDescriptorSource(src grpcurl.DescriptorSource) Option {
+	return func(c *Config) {
+		c.files = src
+	}
+}
+
+func (g *Config) hasFileSource() bool {
+	return g.files != nil || len(g.protoset)+len(g.protosetContent)+len(g.protoFiles)+len(g.protoContent) > 0
+}
+
+// fileSource compose all provided protosets and proto sources to single descriptor source
+func (g *Config) fileSource() (grpcurl.DescriptorSource, error) {
+	if g.files != nil {
+		return g.files, nil
+	}
+
+	var files []*desc.FileDescriptor
+
+	sets := append([][]byte{}, g.protosetContent...)
+
+	for _, name := range g.protoset {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not load protoset file %q: %w", name, err)
+		}
+
+		sets = append(sets, b)
+	}
+
+	if len(sets) > 0 {
+		// sets commonly share imports, so merge them into one set before linking
+		var all descpb.FileDescriptorSet
+
+		seen := make(map[string]bool)
+
+		for _, b := range sets {
+			var fds descpb.FileDescriptorSet
+			if err := proto.Unmarshal(b, &fds); err != nil {
+				return nil, fmt.Errorf("could not parse protoset: %w", err)
+			}
+
+			for _, fd := range fds.File {
+				if !seen[fd.GetName()] {
+					seen[fd.GetName()] = true
+					all.File = append(all.File, fd)
+				}
+			}
+		}
+
+		m, err := desc.CreateFileDescriptorsFromSet(&all)
+		if err != nil {
+			return nil, fmt.Errorf("could not create descriptors from protoset: %w", err)
+		}
+
+		for _, fd := range m {
+			files = append(files, fd)
+		}
+	}
+
+	names := append([]string{}, g.protoFiles...)
+	for name := range g.protoContent {
+		names = append(names, name)
+	}
+
+	if len(names) > 0 {
+		// map iteration is random but we want stable errors
+		sort.Strings(names)
+
+		p := protoparse.Parser{
+			ImportPaths:           g.importPaths,
+			IncludeSourceCodeInfo: true,
+			Accessor:              g.protoAccessor,
+		}
+
+		fds, err := p.ParseFiles(names...)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse given proto files: %w", err)
+		}
+
+		files = append(files, fds...)
+	}
+
+	return grpcurl.DescriptorSourceFromFileDescriptors(files...)
+}
+
+// protoAccessor looks up in-memory sources first and falls back to file system
+func (g *Config) protoAccessor(name string) (io.ReadCloser, error) {
+	if content, ok := g.protoContent[name]; ok {
+		return ioutil.NopCloser(strings.NewReader(content)), nil
+	}
+
+	return os.Open(name)
+}
+
+// compositeSource prefers server reflection and uses files when reflection is disabled or incomplete
+type compositeSource struct {
+	reflection grpcurl.DescriptorSource
+	file       grpcurl.DescriptorSource
+}
+
+func (cs compositeSource) ListServices() ([]string, error) {
+	res, err := cs.reflection.ListServices()
+	if err != nil {
+		return cs.file.ListServices()
+	}
+
+	return res, nil
+}
+
+func (cs compositeSource) FindSymbol(fullyQualifiedName string) (desc.Descriptor, error) {
+	d, err := cs.reflection.FindSymbol(fullyQualifiedName)
+	if err == nil {
+		return d, nil
+	}
+
+	return cs.file.FindSymbol(fullyQualifiedName)
+}
+
+func (cs compositeSource) AllExtensionsForType(typeName string) ([]*desc.FieldDescriptor, error) {
+	exts, err := cs.reflection.AllExtensionsForType(typeName)
+	if err != nil {
+		// On error fall back to file source
+		return cs.file.AllExtensionsForType(typeName)
+	}
+
+	// Track the tag numbers from the reflection source
+	tags := make(map[int32]bool)
+	for _, ext := range exts {
+		tags[ext.GetNumber()] = true
+	}
+
+	fileExts, err := cs.file.AllExtensionsForType(typeName)
+	if err != nil {
+		return exts, nil
+	}
+
+	for _, ext := range fileExts {
+		// Prioritize extensions found via reflection
+		if !tags[ext.GetNumber()] {
+			exts = append(exts, ext)
+		}
+	}
+
+	return exts, nil
+}
+
+// Descriptors returns source composed of WithProtoset, WithProtosetContent, WithProtoFiles and WithProtoContent
+func (g *Config) Descriptors() (grpcurl.DescriptorSource, error) {
+	if !g.hasFileSource() {
+		return nil, ErrNoDescriptors
+	}
+
+	return g.fileSource()
+}
+
+// Sources keeps descriptor sources of scenario, so protosets and proto files are parsed once for all its actions
+type Sources struct {
+	mu sync.Mutex
+	// key: descriptors of action
+	sources map[string]grpcurl.DescriptorSource
+}
+
+func NewSources() *Sources {
+	return &Sources{sources: make(map[string]grpcurl.DescriptorSource)}
+}
+
+// Get returns source of key, options are requested and parsed until it succeeds,
+// so descriptors which aren't available yet are loaded by the later call
+func (s *Sources) Get(key string, options func() ([]Option, error)) (grpcurl.DescriptorSource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if src, ok := s.sources[key]; ok {
+		return src, nil
+	}
+
+	opt, err := options()
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := New(opt...).Descriptors()
+	if err != nil {
+		return nil, err
+	}
+
+	s.sources[key] = src
+
+	return src, nil
+}
@@ -0,0 +1,160 @@
+package grpcexec
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	descpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	pb "google.golang.org/grpc/examples/helloworld/helloworld"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+const helloworldProto = `syntax = "proto3";
+
+package helloworld;
+
+service Greeter {
+  rpc SayHello (HelloRequest) returns (HelloReply) {}
+}
+
+message HelloRequest {
+  string name = 1;
+}
+
+message HelloReply {
+  string message = 1;
+}
+`
+
+func helloworldProtoset(t *testing.T) []byte {
+	md, err := desc.LoadMessageDescriptorForMessage(&pb.HelloRequest{})
+	assert.NoError(t, err)
+
+	b, err := proto.Marshal(&descpb.FileDescriptorSet{
+		File: []*descpb.FileDescriptorProto{md.GetFile().AsFileDescriptorProto()},
+	})
+	assert.NoError(t, err)
+
+	return b
+}
+
+// TestDescriptorSource calls server with disabled reflection
+func TestDescriptorSource(t *testing.T) {
+	name := "TEST_NAME"
+
+	l, srv := CreateMockServer(Fixture{
+		Res:          &pb.HelloReply{Message: "OK"},
+		CB:           func(req *pb.HelloRequest) { assert.Equal(t, name, req.Name) },
+		NoReflection: true,
+	})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	dir, err := ioutil.TempDir("", "grpcexec")
+	assert.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	protoset := filepath.Join(dir, "helloworld.protoset")
+	assert.NoError(t, ioutil.WriteFile(protoset, helloworldProtoset(t), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "helloworld.proto"), []byte(helloworldProto), 0600))
+
+	tests := []struct {
+		name string
+		opt  []Option
+	}{
+		{"protoset file", []Option{WithProtoset(protoset)}},
+		{"protoset content", []Option{WithProtosetContent(helloworldProtoset(t))}},
+		{"proto file", []Option{WithProtoFiles([]string{dir}, "helloworld.proto")}},
+		{"proto content", []Option{WithProtoContent(map[string]string{"helloworld.proto": helloworldProto})}},
+	}
+
+	path := Path{
+		Package: "helloworld",
+		Service: "Greeter",
+		RPC:     "SayHello",
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, body, err := New(tt.opt...).Call(context.Background(), l.Addr().String(), path, `{"name":"TEST_NAME"}`)
+			assert.NoError(t, err)
+			assert.Equal(t, codes.OK, c)
+
+			out := make(map[string]string)
+			assert.NoError(t, json.Unmarshal(body, &out))
+			assert.Equal(t, map[string]string{"message": "OK"}, out)
+		})
+	}
+}
+
+func TestDescriptorSourceReflectionOnly(t *testing.T) {
+	l, srv := CreateMockServer(Fixture{NoReflection: true})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	path := Path{
+		Package: "helloworld",
+		Service: "Greeter",
+		RPC:     "SayHello",
+	}
+
+	_, _, err := New().Call(context.Background(), l.Addr().String(), path, `{}`)
+	assert.Error(t, err)
+}
+
+func TestSources(t *testing.T) {
+	l, srv := CreateMockServer(Fixture{
+		Res:          &pb.HelloReply{Message: "OK"},
+		CB:           func(*pb.HelloRequest) {},
+		NoReflection: true,
+	})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	sources := NewSources()
+	errMissing := errors.New("config map not found")
+
+	parsed := 0
+	options := func(err error) func() ([]Option, error) {
+		return func() ([]Option, error) {
+			parsed++
+			return []Option{WithProtoContent(map[string]string{"helloworld.proto": helloworldProto})}, err
+		}
+	}
+
+	// failure isn't cached
+	_, err := sources.Get("helloworld", options(errMissing))
+	assert.ErrorIs(t, err, errMissing)
+
+	for i := 0; i < 2; i++ {
+		src, err := sources.Get("helloworld", options(nil))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		c, _, err := New(WithDescriptorSource(src)).Call(context.Background(), l.Addr().String(), Path{
+			Package: "helloworld",
+			Service: "Greeter",
+			RPC:     "SayHello",
+		}, `{"name":"TEST_NAME"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, codes.OK, c)
+	}
+
+	assert.Equal(t, 2, parsed)
+
+	_, err = sources.Get("empty", func() ([]Option, error) { return nil, nil })
+	assert.ErrorIs(t, err, ErrNoDescriptors)
+}
@@ -0,0 +1,135 @@
+package grpcexec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fullstorydev/grpcurl"
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// StreamLimit stops receiving of responses before server closes stream
+type StreamLimit struct {
+	// Messages max number of response messages, 0 means unlimited
+	Messages int
+	// Duration of receiving window, 0 means until server closes stream
+	Duration time.Duration
+}
+
+// Stream invokes client, server or bidi streaming rpc.
+// Also works for unary rpc with single request.
+// @symbol: {package}.{service}/{rpc}
+// @requests - list of json requests which are sent one by one
+// Returns json array of received messages.
+// Stream stopped by limit is not an error and returns codes.OK
+func (g *service) Stream(ctx context.Context, addr string, symbol Path, requests []string, limit StreamLimit) (codes.Code, []byte, error) {
+	cc, release, err := g.conn(ctx, addr)
+	if err != nil {
+		return 0, nil, fmt.Errorf("call error: %w", err)
+	}
+
+	defer release()
+
+	descSource, err := g.descriptorSource(ctx, cc)
+	if err != nil {
+		return 0, nil, fmt.Errorf("descriptor source error: %w", err)
+	}
+
+	// messages are collected to json array, so another format is useless here
+	in := strings.NewReader(strings.Join(requests, "\n"))
+	rf, formatter, err := grpcurl.RequestParserAndFormatter(grpcurl.FormatJSON, descSource, in, g.formatOptions())
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to construct request parser and formatter: %w", err)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if limit.Duration > 0 {
+		streamCtx, cancel = context.WithTimeout(streamCtx, limit.Duration)
+		defer cancel()
+	}
+
+	h := &streamHandler{
+		formatter: formatter,
+		limit:     limit.Messages,
+		cancel:    cancel,
+		messages:  make([]json.RawMessage, 0),
+	}
+
+	err = grpcurl.InvokeRPC(streamCtx, descSource, cc, symbol.String(), nil, h, rf.Next)
+	if err != nil {
+		errStatus, ok := status.FromError(err)
+		if !ok {
+			return 0, nil, fmt.Errorf("error invoking method %q: %w", symbol, err)
+		}
+
+		h.status = errStatus
+	}
+
+	if h.err != nil {
+		return 0, nil, fmt.Errorf("format response of %q: %w", symbol, h.err)
+	}
+
+	body, err := json.Marshal(h.messages)
+	if err != nil {
+		return 0, nil, fmt.Errorf("marshal responses of %q: %w", symbol, err)
+	}
+
+	// we break stream ourselves when limit reached or receiving window is over
+	if h.limited || (ctx.Err() == nil && streamCtx.Err() == context.DeadlineExceeded) {
+		return codes.OK, body, nil
+	}
+
+	return h.status.Code(), body, nil
+}
+
+// streamHandler collects formatted responses
+type streamHandler struct {
+	formatter grpcurl.Formatter
+	limit     int
+	cancel    context.CancelFunc
+
+	messages []json.RawMessage
+	status   *status.Status
+	limited  bool
+	err      error
+}
+
+func (s *streamHandler) OnResolveMethod(*desc.MethodDescriptor) {}
+
+func (s *streamHandler) OnSendHeaders(metadata.MD) {}
+
+func (s *streamHandler) OnReceiveHeaders(metadata.MD) {}
+
+func (s *streamHandler) OnReceiveResponse(msg proto.Message) {
+	if s.limited || s.err != nil {
+		return
+	}
+
+	res, err := s.formatter(msg)
+	if err != nil {
+		s.err = err
+		s.cancel()
+
+		return
+	}
+
+	s.messages = append(s.messages, json.RawMessage(res))
+
+	if s.limit > 0 && len(s.messages) >= s.limit {
+		s.limited = true
+		s.cancel()
+	}
+}
+
+func (s *streamHandler) OnReceiveTrailers(stat *status.Status, _ metadata.MD) {
+	s.status = stat
+}
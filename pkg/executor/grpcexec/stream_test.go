@@ -0,0 +1,103 @@
+package grpcexec
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+func TestStream(t *testing.T) {
+	l, srv := CreateEchoServer(EchoFixture{Repeat: 3})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	path := func(rpc string) Path {
+		return Path{Package: "grpc.examples.echo", Service: "Echo", RPC: rpc}
+	}
+
+	tests := []struct {
+		name     string
+		rpc      string
+		requests []string
+		limit    StreamLimit
+		want     []string
+	}{
+		{
+			"unary",
+			"UnaryEcho",
+			[]string{`{"message":"A"}`},
+			StreamLimit{},
+			[]string{"A"},
+		},
+		{
+			"server stream",
+			"ServerStreamingEcho",
+			[]string{`{"message":"A"}`},
+			StreamLimit{},
+			[]string{"A", "A", "A"},
+		},
+		{
+			"server stream with limit",
+			"ServerStreamingEcho",
+			[]string{`{"message":"A"}`},
+			StreamLimit{Messages: 2},
+			[]string{"A", "A"},
+		},
+		{
+			"client stream",
+			"ClientStreamingEcho",
+			[]string{`{"message":"A"}`, `{"message":"B"}`},
+			StreamLimit{},
+			[]string{"A B"},
+		},
+		{
+			"bidi",
+			"BidirectionalStreamingEcho",
+			[]string{`{"message":"A"}`, `{"message":"B"}`},
+			StreamLimit{},
+			[]string{"A", "B"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, body, err := New().Stream(context.Background(), l.Addr().String(), path(tt.rpc), tt.requests, tt.limit)
+			assert.NoError(t, err)
+			assert.Equal(t, codes.OK, c)
+
+			var out []map[string]string
+			assert.NoError(t, json.Unmarshal(body, &out))
+
+			got := make([]string, 0, len(out))
+			for _, m := range out {
+				got = append(got, m["message"])
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStreamDuration(t *testing.T) {
+	// infinite stream
+	l, srv := CreateEchoServer(EchoFixture{Interval: 10 * time.Millisecond})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	path := Path{Package: "grpc.examples.echo", Service: "Echo", RPC: "ServerStreamingEcho"}
+
+	c, body, err := New().Stream(context.Background(), l.Addr().String(), path,
+		[]string{`{"message":"A"}`}, StreamLimit{Duration: 100 * time.Millisecond})
+	assert.NoError(t, err)
+	assert.Equal(t, codes.OK, c)
+
+	var out []map[string]string
+	assert.NoError(t, json.Unmarshal(body, &out))
+	assert.NotEmpty(t, out)
+}
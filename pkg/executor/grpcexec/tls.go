@@ -0,0 +1,97 @@
+package grpcexec
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/fullstorydev/grpcurl"
+	"google.golang.org/grpc/credentials"
+)
+
+// WithTLS turns on TLS with verification of server certificate.
+// cacert, cert and key are file paths, cert and key are required for mTLS only
+func WithTLS(cacert, cert, key string) Option {
+	return func(c *Config) {
+		c.plaintext = false
+		c.insecure = false
+		c.cacert, c.cert, c.key = cacert, cert, key
+	}
+}
+
+// WithTLSContent same as WithTLS but takes PEM encoded content instead of file paths
+func WithTLSContent(cacert, cert, key []byte) Option {
+	return func(c *Config) {
+		c.plaintext = false
+		c.insecure = false
+		c.cacertPEM, c.certPEM, c.keyPEM = cacert, cert, key
+	}
+}
+
+// WithInsecure turns on TLS without verification of server certificate
+func WithInsecure() Option {
+	return func(c *Config) {
+		c.plaintext = false
+		c.insecure = true
+	}
+}
+
+// WithServerName overrides server name used for verification of server certificate
+func WithServerName(name string) Option {
+	return func(c *Config) {
+		c.serverName = name
+	}
+}
+
+// WithAuthority overrides :authority pseudo header, also used as server name for TLS
+func WithAuthority(authority string) Option {
+	return func(c *Config) {
+		c.authority = authority
+	}
+}
+
+func (g *Config) transportCredentials() (credentials.TransportCredentials, error) {
+	if len(g.cacertPEM)+len(g.certPEM)+len(g.keyPEM) == 0 {
+		return grpcurl.ClientTransportCredentials(g.insecure, g.cacert, g.cert, g.key)
+	}
+
+	var conf tls.Config
+
+	if len(g.certPEM) > 0 || len(g.keyPEM) > 0 {
+		cert, err := tls.X509KeyPair(g.certPEM, g.keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client key pair: %w", err)
+		}
+
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	if g.insecure {
+		conf.InsecureSkipVerify = true
+	} else if len(g.cacertPEM) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(g.cacertPEM) {
+			return nil, fmt.Errorf("could not append ca certificate")
+		}
+
+		conf.RootCAs = pool
+	}
+
+	return credentials.NewTLS(&conf), nil
+}
+
+// tlsHash identifies in-memory credentials without exposing them
+func (g *Config) tlsHash() string {
+	if len(g.cacertPEM)+len(g.certPEM)+len(g.keyPEM) == 0 {
+		return ""
+	}
+
+	h := sha256.New()
+	for _, b := range [][]byte{g.cacertPEM, g.certPEM, g.keyPEM} {
+		_, _ = h.Write(b)
+		_, _ = h.Write([]byte{0})
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
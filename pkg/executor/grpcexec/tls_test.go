@@ -0,0 +1,61 @@
+package grpcexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	pb "google.golang.org/grpc/examples/helloworld/helloworld"
+)
+
+func TestTLS(t *testing.T) {
+	fx, err := CreateTLSFixture()
+	assert.NoError(t, err)
+
+	l, srv := CreateMockServer(Fixture{
+		Res: &pb.HelloReply{Message: "OK"},
+		CB:  func(req *pb.HelloRequest) {},
+		TLS: fx.Server,
+	})
+
+	defer l.Close()
+	defer srv.Stop()
+
+	path := Path{
+		Package: "helloworld",
+		Service: "Greeter",
+		RPC:     "SayHello",
+	}
+
+	tests := []struct {
+		name    string
+		opt     []Option
+		wantErr bool
+	}{
+		{"mtls", []Option{WithTLSContent(fx.CA, fx.ClientCert, fx.ClientKey), WithServerName(TLSServerName)}, false},
+		{"plaintext", nil, true},
+		{"unknown ca", []Option{WithTLSContent(fx.ClientCert, fx.ClientCert, fx.ClientKey), WithServerName(TLSServerName)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, err := New(append(tt.opt, withConnectTimeout(1))...).
+				Call(context.Background(), l.Addr().String(), path, `{}`)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, codes.OK, c)
+		})
+	}
+}
+
+func withConnectTimeout(sec float64) Option {
+	return func(c *Config) {
+		c.connectTimeout = sec
+	}
+}
@@ -0,0 +1,156 @@
+package httpexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultContentType = "application/json"
+
+	// defaultMaxBodySize of response
+	defaultMaxBodySize = 10 << 20
+)
+
+var ErrBodyTooLarge = errors.New("response body is too large")
+
+// Option dynamically change any internal requirements
+type Option func(*Config)
+
+// Config extend http client for enhance opt use Option which you should write inside this package ;)
+type Config struct {
+	timeout     time.Duration
+	client      *http.Client
+	maxBodySize int64
+}
+
+// WithTimeout limits whole request time including reading of response body
+func WithTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = d
+	}
+}
+
+// WithClient replace default http client, for example with custom transport
+func WithClient(cl *http.Client) Option {
+	return func(c *Config) {
+		c.client = cl
+	}
+}
+
+// WithMaxBodySize limits response body, call fails when it's exceeded
+func WithMaxBodySize(n int64) Option {
+	return func(c *Config) {
+		c.maxBodySize = n
+	}
+}
+
+// Request describes single http call
+type Request struct {
+	// Addr full url with scheme
+	Addr   string
+	Method string
+	Header map[string]string
+	Query  map[string]string
+	Body   []byte
+}
+
+type service struct {
+	Config
+}
+
+func New(opt ...Option) *service {
+	c := &Config{
+		timeout:     30 * time.Second,
+		client:      http.DefaultClient,
+		maxBodySize: defaultMaxBodySize,
+	}
+
+	for _, opt := range opt {
+		opt(c)
+	}
+
+	return &service{Config: *c}
+}
+
+// Call perform request and returns response status code, headers and body
+func (s *service) Call(ctx context.Context, r Request) (int, http.Header, []byte, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	req, err := s.request(ctx, r)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("build request error: %w", err)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("call %s %q error: %w", req.Method, req.URL, err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	// one byte over limit tells that body is truncated
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, s.maxBodySize+1))
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("read response body error: %w", err)
+	}
+
+	if int64(len(body)) > s.maxBodySize {
+		return 0, nil, nil, fmt.Errorf("%w: more than %d bytes", ErrBodyTooLarge, s.maxBodySize)
+	}
+
+	return res.StatusCode, res.Header, body, nil
+}
+
+func (s *service) request(ctx context.Context, r Request) (*http.Request, error) {
+	u, err := url.Parse(r.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("bad addr %q: %w", r.Addr, err)
+	}
+
+	if len(r.Query) > 0 {
+		q := u.Query()
+		for k, v := range r.Query {
+			q.Set(k, v)
+		}
+
+		u.RawQuery = q.Encode()
+	}
+
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if len(r.Body) > 0 {
+		body = bytes.NewReader(r.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range r.Header {
+		req.Header.Set(k, v)
+	}
+
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", defaultContentType)
+	}
+
+	return req, nil
+}
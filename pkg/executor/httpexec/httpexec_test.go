@@ -0,0 +1,75 @@
+package httpexec
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHTTP shows all flow about request building and response mapping
+func TestHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/hello", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("page"))
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+		assert.Equal(t, defaultContentType, r.Header.Get("Content-Type"))
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"TEST_NAME"}`, string(body))
+
+		w.Header().Set("X-Request-Id", "42")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"message":"OK"}`))
+	}))
+
+	defer srv.Close()
+
+	code, header, body, err := New().Call(context.Background(), Request{
+		Addr:   srv.URL + "/api/v1/hello",
+		Method: http.MethodPost,
+		Header: map[string]string{"Authorization": "token"},
+		Query:  map[string]string{"page": "1"},
+		Body:   []byte(`{"name":"TEST_NAME"}`),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, "42", header.Get("X-Request-Id"))
+	assert.Equal(t, `{"message":"OK"}`, string(body))
+}
+
+func TestHTTPDefaultMethod(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Empty(t, r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	defer srv.Close()
+
+	code, _, body, err := New().Call(context.Background(), Request{Addr: srv.URL})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Empty(t, body)
+}
+
+func TestHTTPMaxBodySize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"message":"OK"}`))
+	}))
+
+	defer srv.Close()
+
+	_, _, body, err := New(WithMaxBodySize(16)).Call(context.Background(), Request{Addr: srv.URL})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"message":"OK"}`, string(body))
+
+	_, _, _, err = New(WithMaxBodySize(15)).Call(context.Background(), Request{Addr: srv.URL})
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+}
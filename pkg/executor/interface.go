@@ -1 +1,44 @@
 package executor
+
+import (
+	"context"
+	"sync"
+)
+
+// Executor performs single action of scenario event
+type Executor interface {
+	Call(ctx context.Context) (*Result, error)
+}
+
+// Validator is optionally implemented by executors which are able to check
+// their configuration before scenario starts
+type Validator interface {
+	Validate(ctx context.Context) error
+}
+
+// Preparer is optionally implemented by executors which require resources registered
+// before scenario starts, e.g. to expose them to earlier actions
+type Preparer interface {
+	Prepare(ctx context.Context) error
+}
+
+// Env contains scenario scoped dependencies which executors may require
+type Env struct {
+	// Namespace of scenario, referenced resources are looked up here
+	Namespace string
+	// Key of scenario which owns resources created by actions
+	Key string
+	// Vars is variable store of scenario
+	Vars *sync.Map
+	// Services shared by executors, e.g. kubernetes client or connection pools
+	Services Services
+}
+
+// Services are dependencies of executors by name. Core package doesn't know their types,
+// package which provides service and executors which use it agree on its name and type
+type Services map[string]interface{}
+
+// Service returns dependency registered under name, nil when it isn't provided
+func (e Env) Service(name string) interface{} {
+	return e.Services[name]
+}
@@ -0,0 +1,253 @@
+package jobexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultPollInterval = time.Second
+
+	// jobNameLabel is set by job controller on pods
+	jobNameLabel = "job-name"
+
+	// ScenarioAnnotation contains key of scenario which started job
+	ScenarioAnnotation = "karness.io/scenario"
+)
+
+var (
+	ErrNoContainer   = errors.New("container not found")
+	ErrNotTerminated = errors.New("container wasn't terminated")
+)
+
+type Option func(*Config)
+
+type Config struct {
+	pollInterval time.Duration
+	keep         bool
+	owner        string
+}
+
+// WithPollInterval sets interval of job status checks, 1s by default
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.pollInterval = d
+	}
+}
+
+// WithKeep leaves finished job in cluster, by default job and its pods are deleted
+func WithKeep(keep bool) Option {
+	return func(c *Config) {
+		c.keep = keep
+	}
+}
+
+// WithOwner annotates job with scenario key
+func WithOwner(key string) Option {
+	return func(c *Config) {
+		c.owner = key
+	}
+}
+
+type Request struct {
+	Namespace string
+	// Name prefix of generated job name
+	Name     string
+	Template corev1.PodTemplateSpec
+	// Container which exit code and logs are returned, first container by default
+	Container string
+	// Timeout of job including pod scheduling, 0 means until ctx is done
+	Timeout time.Duration
+}
+
+type service struct {
+	Config
+	client kubernetes.Interface
+}
+
+func New(client kubernetes.Interface, opt ...Option) *service {
+	c := Config{pollInterval: defaultPollInterval}
+
+	for _, o := range opt {
+		o(&c)
+	}
+
+	return &service{Config: c, client: client}
+}
+
+// Run starts job with single pod, waits until it finishes and returns exit code and logs of container
+func (s *service) Run(ctx context.Context, r Request) (int32, []byte, error) {
+	container, err := containerName(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	job, err := s.client.BatchV1().Jobs(r.Namespace).Create(ctx, s.job(r), metav1.CreateOptions{})
+	if err != nil {
+		return 0, nil, fmt.Errorf("create job: %w", err)
+	}
+
+	if !s.keep {
+		defer s.delete(job)
+	}
+
+	if job, err = s.wait(ctx, job); err != nil {
+		return 0, nil, err
+	}
+
+	pod, err := s.pod(ctx, job, container)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	logs, err := s.client.CoreV1().Pods(pod.Namespace).
+		GetLogs(pod.Name, &corev1.PodLogOptions{Container: container}).DoRaw(ctx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("pod %s logs: %w", pod.Name, err)
+	}
+
+	return exitCode(pod, container), logs, nil
+}
+
+func (s *service) job(r Request) *batchv1.Job {
+	backoff := int32(0)
+
+	tpl := *r.Template.DeepCopy()
+	if tpl.Spec.RestartPolicy == "" {
+		tpl.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: r.Name + "-",
+			Namespace:    r.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			// result of single run is reported, scenario decides about retries
+			BackoffLimit: &backoff,
+			Template:     tpl,
+		},
+	}
+
+	if s.owner != "" {
+		job.Annotations = map[string]string{ScenarioAnnotation: s.owner}
+	}
+
+	if r.Timeout > 0 {
+		deadline := int64(r.Timeout / time.Second)
+		if deadline == 0 {
+			deadline = 1
+		}
+
+		job.Spec.ActiveDeadlineSeconds = &deadline
+	}
+
+	return job
+}
+
+// wait until job succeeded or failed
+func (s *service) wait(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error) {
+	t := time.NewTicker(s.pollInterval)
+	defer t.Stop()
+
+	for {
+		if job.Status.Succeeded > 0 || job.Status.Failed > 0 {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("job %s wait: %w", job.Name, ctx.Err())
+		case <-t.C:
+		}
+
+		var err error
+		if job, err = s.client.BatchV1().Jobs(job.Namespace).Get(ctx, job.Name, metav1.GetOptions{}); err != nil {
+			return nil, fmt.Errorf("get job: %w", err)
+		}
+	}
+}
+
+// pod returns last pod of job where container terminated
+func (s *service) pod(ctx context.Context, job *batchv1.Job, container string) (*corev1.Pod, error) {
+	selector := labels.Set{jobNameLabel: job.Name}.String()
+	if job.Spec.Selector != nil && len(job.Spec.Selector.MatchLabels) > 0 {
+		selector = labels.Set(job.Spec.Selector.MatchLabels).String()
+	}
+
+	list, err := s.client.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("job %s pods: %w", job.Name, err)
+	}
+
+	pods := list.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+	})
+
+	for i := range pods {
+		for _, st := range pods[i].Status.ContainerStatuses {
+			if st.Name == container && st.State.Terminated != nil {
+				return &pods[i], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("job %s container %s: %w", job.Name, container, ErrNotTerminated)
+}
+
+func (s *service) delete(job *batchv1.Job) {
+	propagation := metav1.DeletePropagationBackground
+
+	err := s.client.BatchV1().Jobs(job.Namespace).Delete(context.Background(), job.Name,
+		metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		klog.Errorf("delete job %s/%s: %v", job.Namespace, job.Name, err)
+	}
+}
+
+func containerName(r Request) (string, error) {
+	containers := r.Template.Spec.Containers
+	if len(containers) == 0 {
+		return "", fmt.Errorf("pod template: %w", ErrNoContainer)
+	}
+
+	if r.Container == "" {
+		return containers[0].Name, nil
+	}
+
+	for _, c := range containers {
+		if c.Name == r.Container {
+			return c.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrNoContainer, r.Container)
+}
+
+func exitCode(pod *corev1.Pod, container string) int32 {
+	for _, st := range pod.Status.ContainerStatuses {
+		if st.Name == container {
+			return st.State.Terminated.ExitCode
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,134 @@
+package jobexec
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+)
+
+var template = corev1.PodTemplateSpec{
+	Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "main", Image: "busybox"}},
+	},
+}
+
+// newClient emulates job controller: job finishes immediately and its pod terminates with exit code
+func newClient(exit int32) *fake.Clientset {
+	c := fake.NewSimpleClientset()
+
+	c.PrependReactor("create", "jobs", func(action core.Action) (bool, runtime.Object, error) {
+		job := action.(core.CreateAction).GetObject().(*batchv1.Job)
+		job.Name = job.GenerateName + "x"
+
+		if exit == 0 {
+			job.Status.Succeeded = 1
+		} else {
+			job.Status.Failed = 1
+		}
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      job.Name + "-pod",
+				Namespace: job.Namespace,
+				Labels:    map[string]string{jobNameLabel: job.Name},
+			},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "main",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exit}},
+			}}},
+		}
+
+		return false, nil, c.Tracker().Add(pod)
+	})
+
+	return c
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		exit int32
+	}{
+		{"succeeded", 0},
+		{"failed", 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newClient(test.exit)
+
+			code, logs, err := New(c, WithOwner("default/test")).Run(context.Background(), Request{
+				Namespace: "default",
+				Name:      "check",
+				Template:  template,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.exit, code)
+			assert.Equal(t, "fake logs", string(logs))
+
+			jobs, err := c.BatchV1().Jobs("default").List(context.Background(), metav1.ListOptions{})
+			assert.NoError(t, err)
+			assert.Empty(t, jobs.Items, "job is deleted")
+		})
+	}
+}
+
+func TestRunKeep(t *testing.T) {
+	c := newClient(0)
+
+	_, _, err := New(c, WithKeep(true), WithOwner("default/test")).Run(context.Background(), Request{
+		Namespace: "default",
+		Name:      "check",
+		Template:  template,
+		Timeout:   time.Minute,
+	})
+	assert.NoError(t, err)
+
+	job, err := c.BatchV1().Jobs("default").Get(context.Background(), "check-x", metav1.GetOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "default/test", job.Annotations[ScenarioAnnotation])
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Equal(t, int64(60), *job.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+}
+
+func TestRunTimeout(t *testing.T) {
+	c := fake.NewSimpleClientset()
+	c.PrependReactor("create", "jobs", func(action core.Action) (bool, runtime.Object, error) {
+		job := action.(core.CreateAction).GetObject().(*batchv1.Job)
+		job.Name = job.GenerateName + "x"
+
+		return false, nil, nil
+	})
+
+	_, _, err := New(c, WithPollInterval(10*time.Millisecond)).Run(context.Background(), Request{
+		Namespace: "default",
+		Name:      "check",
+		Template:  template,
+		Timeout:   50 * time.Millisecond,
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRunContainer(t *testing.T) {
+	_, _, err := New(newClient(0)).Run(context.Background(), Request{
+		Namespace: "default",
+		Template:  template,
+		Container: "sidecar",
+	})
+
+	assert.ErrorIs(t, err, ErrNoContainer)
+}
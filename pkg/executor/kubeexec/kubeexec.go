@@ -0,0 +1,196 @@
+package kubeexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// Verb of operation over object
+type Verb string
+
+const (
+	Create Verb = "create"
+	Apply  Verb = "apply"
+	Patch  Verb = "patch"
+	Get    Verb = "get"
+	Delete Verb = "delete"
+)
+
+const defaultFieldManager = "karness"
+
+var (
+	ErrUnknownVerb      = errors.New("unknown verb")
+	ErrUnknownPatchType = errors.New("unknown patch type")
+	ErrBadManifest      = errors.New("bad manifest")
+	ErrForeignNamespace = errors.New("manifest namespace differs from request namespace")
+)
+
+// patchTypes maps short names used in scenario to patch types
+var patchTypes = map[string]types.PatchType{
+	"":          types.MergePatchType,
+	"merge":     types.MergePatchType,
+	"json":      types.JSONPatchType,
+	"strategic": types.StrategicMergePatchType,
+}
+
+// Resolver returns client of resources of given kind.
+// namespace is ignored for cluster scoped resources.
+type Resolver func(ctx context.Context, gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error)
+
+type Option func(*Config)
+
+type Config struct {
+	fieldManager string
+	anyNamespace bool
+}
+
+// WithFieldManager sets field manager of server side apply, karness by default
+func WithFieldManager(name string) Option {
+	return func(c *Config) {
+		c.fieldManager = name
+	}
+}
+
+// WithAnyNamespace allows manifests to operate objects of namespaces other than request namespace
+func WithAnyNamespace() Option {
+	return func(c *Config) {
+		c.anyNamespace = true
+	}
+}
+
+type Request struct {
+	Verb Verb
+	// Namespace is used when manifest doesn't specify one,
+	// manifest of other namespace is rejected unless any namespace is allowed
+	Namespace string
+	// Manifest YAML or JSON object.
+	// get, patch and delete require only apiVersion, kind and metadata
+	Manifest []byte
+	// Patch body of patch verb
+	Patch []byte
+	// PatchType merge, json or strategic, merge by default
+	PatchType string
+}
+
+type service struct {
+	Config
+	resolve Resolver
+}
+
+func New(resolve Resolver, opt ...Option) *service {
+	c := Config{fieldManager: defaultFieldManager}
+
+	for _, o := range opt {
+		o(&c)
+	}
+
+	return &service{Config: c, resolve: resolve}
+}
+
+// Validate checks request without calling API
+func Validate(r Request) error {
+	switch r.Verb {
+	case Create, Apply, Get, Delete:
+	case Patch:
+		if _, ok := patchTypes[r.PatchType]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownPatchType, r.PatchType)
+		}
+
+		if len(r.Patch) == 0 {
+			return fmt.Errorf("patch body is required")
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownVerb, r.Verb)
+	}
+
+	_, err := decode(r.Manifest)
+
+	return err
+}
+
+// Do performs request and returns resulting object.
+// delete returns object state which was observed just before deletion.
+// API errors are returned as is, so callers could use k8s.io/apimachinery/pkg/api/errors helpers.
+func (s *service) Do(ctx context.Context, r Request) (*unstructured.Unstructured, error) {
+	if err := Validate(r); err != nil {
+		return nil, err
+	}
+
+	obj, _ := decode(r.Manifest)
+
+	ns := obj.GetNamespace()
+
+	switch {
+	case ns == "":
+		ns = r.Namespace
+	case ns != r.Namespace && !s.anyNamespace:
+		return nil, fmt.Errorf("%w: %q", ErrForeignNamespace, ns)
+	}
+
+	client, err := s.resolve(ctx, obj.GroupVersionKind(), ns)
+	if err != nil {
+		return nil, err
+	}
+
+	switch r.Verb {
+	case Create:
+		return client.Create(ctx, obj, metav1.CreateOptions{FieldManager: s.fieldManager})
+	case Apply:
+		data, err := obj.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		force := true
+
+		return client.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+			FieldManager: s.fieldManager,
+			Force:        &force,
+		})
+	case Patch:
+		data, err := yaml.ToJSON(r.Patch)
+		if err != nil {
+			return nil, fmt.Errorf("patch body: %w", err)
+		}
+
+		return client.Patch(ctx, obj.GetName(), patchTypes[r.PatchType], data, metav1.PatchOptions{FieldManager: s.fieldManager})
+	case Get:
+		return client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	default: // Delete
+		res, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		propagation := metav1.DeletePropagationBackground
+
+		return res, client.Delete(ctx, obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+	}
+}
+
+// decode YAML or JSON manifest
+func decode(manifest []byte) (*unstructured.Unstructured, error) {
+	data, err := yaml.ToJSON(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadManifest, err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err = obj.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadManifest, err)
+	}
+
+	if obj.GetName() == "" {
+		return nil, fmt.Errorf("%w: metadata.name is required", ErrBadManifest)
+	}
+
+	return obj, nil
+}
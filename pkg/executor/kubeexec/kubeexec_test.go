@@ -0,0 +1,129 @@
+package kubeexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const manifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+data:
+  key: value
+`
+
+func newService() *service {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+
+	return New(NewResolver(dynamicfake.NewSimpleDynamicClient(scheme.Scheme), mapper))
+}
+
+func TestDo(t *testing.T) {
+	s := newService()
+	ctx := context.Background()
+
+	steps := []struct {
+		name   string
+		req    Request
+		expect string
+	}{
+		{"create", Request{Verb: Create, Manifest: []byte(manifest)}, "value"},
+		{"get", Request{Verb: Get, Manifest: []byte(manifest)}, "value"},
+		{"patch", Request{Verb: Patch, Manifest: []byte(manifest), Patch: []byte(`data: {key: patched}`)}, "patched"},
+		{"json-patch", Request{
+			Verb:      Patch,
+			Manifest:  []byte(manifest),
+			Patch:     []byte(`[{"op":"replace","path":"/data/key","value":"json"}]`),
+			PatchType: "json",
+		}, "json"},
+		{"delete", Request{Verb: Delete, Manifest: []byte(manifest)}, "json"},
+	}
+
+	for _, step := range steps {
+		step.req.Namespace = "default"
+
+		res, err := s.Do(ctx, step.req)
+		if !assert.NoError(t, err, step.name) {
+			return
+		}
+
+		val, _, _ := unstructured.NestedString(res.Object, "data", "key")
+		assert.Equal(t, step.expect, val, step.name)
+		assert.Equal(t, "default", res.GetNamespace(), step.name)
+	}
+
+	_, err := s.Do(ctx, Request{Verb: Get, Namespace: "default", Manifest: []byte(manifest)})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		err  error
+	}{
+		{"ok", Request{Verb: Get, Manifest: []byte(manifest)}, nil},
+		{"verb", Request{Verb: "watch", Manifest: []byte(manifest)}, ErrUnknownVerb},
+		{"patch-type", Request{Verb: Patch, Manifest: []byte(manifest), Patch: []byte(`{}`), PatchType: "x"}, ErrUnknownPatchType},
+		{"no-name", Request{Verb: Get, Manifest: []byte(`{"apiVersion":"v1","kind":"ConfigMap"}`)}, ErrBadManifest},
+		{"broken", Request{Verb: Get, Manifest: []byte(`{`)}, ErrBadManifest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.req)
+			if test.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestResolverUnknownKind(t *testing.T) {
+	_, err := newService().Do(context.Background(), Request{
+		Verb:     Get,
+		Manifest: []byte(`{"apiVersion":"example.com/v1","kind":"Foo","metadata":{"name":"x"}}`),
+	})
+
+	assert.True(t, meta.IsNoMatchError(err))
+}
+
+func TestForeignNamespace(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+
+	resolver := NewResolver(dynamicfake.NewSimpleDynamicClient(scheme.Scheme), mapper)
+	req := Request{
+		Verb:      Create,
+		Namespace: "default",
+		Manifest:  []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"x","namespace":"kube-system"}}`),
+	}
+
+	_, err := New(resolver).Do(context.Background(), req)
+	assert.ErrorIs(t, err, ErrForeignNamespace)
+
+	res, err := New(resolver, WithAnyNamespace()).Do(context.Background(), req)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "kube-system", res.GetNamespace())
+}
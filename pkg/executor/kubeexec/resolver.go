@@ -0,0 +1,34 @@
+package kubeexec
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// NewResolver maps kinds to resources with mapper.
+// When kind is unknown and mapper is resettable, e.g. discovery based, mapping is retried once after reset,
+// because scenario could install CRD just before its resources are used.
+func NewResolver(client dynamic.Interface, mapper meta.RESTMapper) Resolver {
+	return func(_ context.Context, gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error) {
+		m, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			if r, ok := mapper.(interface{ Reset() }); ok {
+				r.Reset()
+				m, err = mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if m.Scope.Name() == meta.RESTScopeNameRoot {
+			return client.Resource(m.Resource), nil
+		}
+
+		return client.Resource(m.Resource).Namespace(namespace), nil
+	}
+}
@@ -0,0 +1,126 @@
+package mockexec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fullstorydev/grpcurl"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcCodes maps code names to codes
+var grpcCodes = func() map[string]codes.Code {
+	res := make(map[string]codes.Code)
+
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		res[c.String()] = c
+	}
+
+	return res
+}()
+
+// handleGRPC answers unary and server streaming calls with single message
+func (s *Server) handleGRPC(_ interface{}, stream grpc.ServerStream) error {
+	fullMethod, ok := grpc.MethodFromServerStream(stream)
+	if !ok {
+		return status.Error(codes.Internal, "unknown method")
+	}
+
+	method := strings.TrimPrefix(fullMethod, "/")
+
+	md, err := s.method(method)
+	if err != nil {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+
+	in := dynamic.NewMessage(md.GetInputType())
+	if err = stream.RecvMsg(in); err != nil {
+		return err
+	}
+
+	formatter := grpcurl.NewJSONFormatter(false, grpcurl.AnyResolverFromDescriptorSource(s.descriptors))
+
+	text, err := formatter(in)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	req := new(bytes.Buffer)
+	if err = json.Compact(req, []byte(text)); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	header, _ := metadata.FromIncomingContext(stream.Context())
+	call := Call{Method: method, Header: header, Request: req.Bytes()}
+
+	rule, ok := s.rule(method, req.Bytes())
+	if !ok {
+		call.Code = codes.Unimplemented.String()
+		s.record(call)
+
+		return status.Error(codes.Unimplemented, ErrNoRule.Error())
+	}
+
+	code := codes.OK
+	if rule.Code != "" {
+		if code, ok = grpcCodes[rule.Code]; !ok {
+			return status.Errorf(codes.Internal, "unknown mock code %q", rule.Code)
+		}
+	}
+
+	call.Code = code.String()
+	s.record(call)
+
+	delay(stream.Context(), rule.Delay)
+
+	if len(rule.Header) > 0 {
+		if err = stream.SetHeader(metadata.New(rule.Header)); err != nil {
+			return err
+		}
+	}
+
+	if code != codes.OK {
+		return status.Error(code, string(rule.Body))
+	}
+
+	out := dynamic.NewMessage(md.GetOutputType())
+	if len(rule.Body) > 0 {
+		if err = out.UnmarshalJSON(rule.Body); err != nil {
+			return status.Errorf(codes.Internal, "mock response of %s: %v", method, err)
+		}
+	}
+
+	return stream.SendMsg(out)
+}
+
+// method resolves "package.Service/Method" with descriptors
+func (s *Server) method(method string) (*desc.MethodDescriptor, error) {
+	i := strings.LastIndex(method, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("bad method %q", method)
+	}
+
+	d, err := s.descriptors.FindSymbol(method[:i])
+	if err != nil {
+		return nil, fmt.Errorf("service of %q: %w", method, err)
+	}
+
+	sd, ok := d.(*desc.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not service", method[:i])
+	}
+
+	md := sd.FindMethodByName(method[i+1:])
+	if md == nil {
+		return nil, fmt.Errorf("method %q not found", method)
+	}
+
+	return md, nil
+}
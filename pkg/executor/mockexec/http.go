@@ -0,0 +1,64 @@
+package mockexec
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+func (s *Server) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.Method + " " + r.URL.Path
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := httpRequest(body)
+	call := Call{Method: method, Header: r.Header, Request: req}
+
+	rule, ok := s.rule(method, req)
+	if !ok {
+		call.Code = strconv.Itoa(http.StatusNotFound)
+		s.record(call)
+
+		http.Error(w, ErrNoRule.Error(), http.StatusNotFound)
+
+		return
+	}
+
+	code := http.StatusOK
+	if rule.Code != "" {
+		if code, err = strconv.Atoi(rule.Code); err != nil {
+			http.Error(w, "bad mock code "+rule.Code, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	call.Code = strconv.Itoa(code)
+	s.record(call)
+
+	delay(r.Context(), rule.Delay)
+
+	for k, v := range rule.Header {
+		w.Header().Set(k, v)
+	}
+
+	w.WriteHeader(code)
+	_, _ = w.Write(rule.Body)
+}
+
+// httpRequest is JSON body as is, string of other body or null
+func httpRequest(body []byte) json.RawMessage {
+	switch {
+	case len(body) == 0:
+		return json.RawMessage("null")
+	case json.Valid(body):
+		return body
+	default:
+		b, _ := json.Marshal(string(body))
+		return b
+	}
+}
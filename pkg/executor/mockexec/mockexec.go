@@ -0,0 +1,249 @@
+package mockexec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fullstorydev/grpcurl"
+	"google.golang.org/grpc"
+)
+
+// defaultMaxCalls recorded by server, the oldest calls are dropped
+const defaultMaxCalls = 1000
+
+var ErrNoRule = errors.New("no mock rule matched")
+
+// Matcher selects rule by JSON representation of request
+type Matcher func(req []byte) bool
+
+// Rule answers requests of method, the first matched rule wins
+type Rule struct {
+	// Method grpc "package.Service/Method" or http "METHOD /path"
+	Method string
+	Match  Matcher
+	// Code grpc code name, e.g. NotFound, or http status, OK and 200 by default
+	Code string
+	// Body JSON of grpc response message or http response body,
+	// status message is taken from body when grpc code isn't OK
+	Body   []byte
+	Header map[string]string
+	Delay  time.Duration
+}
+
+// Call recorded by server
+type Call struct {
+	Method string              `json:"method"`
+	Header map[string][]string `json:"header"`
+	// Request JSON, non JSON http body is string
+	Request json.RawMessage `json:"request"`
+	Code    string          `json:"code"`
+	Time    time.Time       `json:"time"`
+}
+
+type Option func(*Config)
+
+type Config struct {
+	grpcAddr    string
+	httpAddr    string
+	descriptors grpcurl.DescriptorSource
+	maxCalls    int
+}
+
+// WithGRPC starts grpc server with services of descriptor source
+func WithGRPC(addr string, src grpcurl.DescriptorSource) Option {
+	return func(c *Config) {
+		c.grpcAddr = addr
+		c.descriptors = src
+	}
+}
+
+// WithHTTP starts http server
+func WithHTTP(addr string) Option {
+	return func(c *Config) {
+		c.httpAddr = addr
+	}
+}
+
+// WithMaxCalls limits number of recorded calls, only the latest calls are kept
+func WithMaxCalls(n int) Option {
+	return func(c *Config) {
+		c.maxCalls = n
+	}
+}
+
+// Server answers grpc and http requests with canned responses of rules and records calls
+type Server struct {
+	Config
+
+	rules []Rule
+
+	mu    sync.Mutex
+	calls []Call
+
+	grpc     *grpc.Server
+	grpcAddr net.Addr
+	http     *http.Server
+	httpAddr net.Addr
+}
+
+func New(rules []Rule, opt ...Option) *Server {
+	c := Config{maxCalls: defaultMaxCalls}
+
+	for _, o := range opt {
+		o(&c)
+	}
+
+	return &Server{Config: c, rules: rules}
+}
+
+// Start listens configured addresses and serves in background
+func (s *Server) Start() error {
+	var gl, hl net.Listener
+
+	if s.descriptors != nil {
+		l, err := net.Listen("tcp", s.Config.grpcAddr)
+		if err != nil {
+			return fmt.Errorf("mock grpc listen: %w", err)
+		}
+
+		gl = l
+	}
+
+	if s.Config.httpAddr != "" {
+		l, err := net.Listen("tcp", s.Config.httpAddr)
+		if err != nil {
+			if gl != nil {
+				_ = gl.Close()
+			}
+
+			return fmt.Errorf("mock http listen: %w", err)
+		}
+
+		hl = l
+	}
+
+	if gl != nil {
+		s.grpc = grpc.NewServer(grpc.UnknownServiceHandler(s.handleGRPC))
+		s.grpcAddr = gl.Addr()
+
+		go func() { _ = s.grpc.Serve(gl) }()
+	}
+
+	if hl != nil {
+		s.http = &http.Server{Handler: http.HandlerFunc(s.handleHTTP)}
+		s.httpAddr = hl.Addr()
+
+		go func() { _ = s.http.Serve(hl) }()
+	}
+
+	return nil
+}
+
+// GRPCAddr is host:port of grpc server reachable by clients, empty when it isn't started
+func (s *Server) GRPCAddr() string {
+	return reachable(s.grpcAddr)
+}
+
+// HTTPAddr is host:port of http server reachable by clients, empty when it isn't started
+func (s *Server) HTTPAddr() string {
+	return reachable(s.httpAddr)
+}
+
+// reachable replaces unspecified listen ip, e.g. [::], with ip of host
+func reachable(a net.Addr) string {
+	tcp, ok := a.(*net.TCPAddr)
+	if !ok {
+		return ""
+	}
+
+	if !tcp.IP.IsUnspecified() {
+		return tcp.String()
+	}
+
+	return net.JoinHostPort(hostIP(), strconv.Itoa(tcp.Port))
+}
+
+// hostIP returns the first non loopback ip of host, loopback when there is no other
+func hostIP() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "127.0.0.1"
+	}
+
+	for _, a := range addrs {
+		if n, ok := a.(*net.IPNet); ok && n.IP.To4() != nil && n.IP.IsGlobalUnicast() {
+			return n.IP.String()
+		}
+	}
+
+	return "127.0.0.1"
+}
+
+// Calls returns recorded calls of method or all calls when method is empty
+func (s *Server) Calls(method string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Call, 0, len(s.calls))
+
+	for _, c := range s.calls {
+		if method == "" || c.Method == method {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+func (s *Server) Close() {
+	if s.grpc != nil {
+		s.grpc.Stop()
+	}
+
+	if s.http != nil {
+		_ = s.http.Close()
+	}
+}
+
+// rule returns the first rule matched request of method
+func (s *Server) rule(method string, req []byte) (Rule, bool) {
+	for _, r := range s.rules {
+		if r.Method == method && (r.Match == nil || r.Match(req)) {
+			return r, true
+		}
+	}
+
+	return Rule{}, false
+}
+
+func (s *Server) record(c Call) {
+	c.Time = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, c)
+
+	if s.maxCalls > 0 && len(s.calls) > s.maxCalls {
+		s.calls = s.calls[len(s.calls)-s.maxCalls:]
+	}
+}
+
+// delay waits for rule delay or until ctx is done
+func delay(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,195 @@
+package mockexec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/d7561985/karness/pkg/executor/grpcexec"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+const helloworldProto = `syntax = "proto3";
+
+package helloworld;
+
+service Greeter {
+  rpc SayHello (HelloRequest) returns (HelloReply) {}
+}
+
+message HelloRequest {
+  string name = 1;
+}
+
+message HelloReply {
+  string message = 1;
+}
+`
+
+func nameIs(name string) Matcher {
+	return func(req []byte) bool {
+		return strings.Contains(string(req), `"name":"`+name+`"`)
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	opt := grpcexec.WithProtoContent(map[string]string{"helloworld.proto": helloworldProto})
+
+	src, err := grpcexec.New(opt).Descriptors()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	s := New([]Rule{
+		{Method: "helloworld.Greeter/SayHello", Match: nameIs("bob"), Code: "NotFound", Body: []byte("no bob")},
+		{Method: "helloworld.Greeter/SayHello", Body: []byte(`{"message":"hello"}`)},
+	}, WithGRPC("127.0.0.1:0", src))
+
+	if !assert.NoError(t, s.Start()) {
+		return
+	}
+
+	defer s.Close()
+
+	path := grpcexec.Path{Package: "helloworld", Service: "Greeter", RPC: "SayHello"}
+
+	tests := []struct {
+		req  string
+		code codes.Code
+		body string
+	}{
+		{`{"name":"alice"}`, codes.OK, `{"message":"hello"}`},
+		{`{"name":"bob"}`, codes.NotFound, `{"code":5,"message":"no bob"}`},
+	}
+
+	for _, test := range tests {
+		code, body, err := grpcexec.New(opt).Call(context.Background(), s.GRPCAddr(), path, test.req)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, test.code, code)
+		assert.JSONEq(t, test.body, string(body))
+	}
+
+	calls := s.Calls("helloworld.Greeter/SayHello")
+	if !assert.Len(t, calls, 2) {
+		return
+	}
+
+	assert.JSONEq(t, `{"name":"alice"}`, string(calls[0].Request))
+	assert.Equal(t, "OK", calls[0].Code)
+	assert.Equal(t, "NotFound", calls[1].Code)
+	assert.Empty(t, s.Calls("helloworld.Greeter/Unknown"))
+}
+
+func TestHTTP(t *testing.T) {
+	s := New([]Rule{
+		{Method: "POST /users", Match: nameIs("bob"), Code: "409"},
+		{Method: "POST /users", Code: "201", Body: []byte(`{"id":1}`), Header: map[string]string{"X-Mock": "1"}},
+	}, WithHTTP("127.0.0.1:0"))
+
+	if !assert.NoError(t, s.Start()) {
+		return
+	}
+
+	defer s.Close()
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		code   int
+		res    string
+	}{
+		{http.MethodPost, "/users", `{"name":"alice"}`, http.StatusCreated, `{"id":1}`},
+		{http.MethodPost, "/users", `{"name":"bob"}`, http.StatusConflict, ``},
+		{http.MethodGet, "/users", ``, http.StatusNotFound, ErrNoRule.Error() + "\n"},
+	}
+
+	for _, test := range tests {
+		req, err := http.NewRequest(test.method, "http://"+s.HTTPAddr()+test.path, bytes.NewBufferString(test.body))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		body, err := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.code, res.StatusCode)
+		assert.Equal(t, test.res, string(body))
+	}
+
+	calls := s.Calls("")
+	if !assert.Len(t, calls, 3) {
+		return
+	}
+
+	assert.Equal(t, "POST /users", calls[0].Method)
+	assert.Equal(t, `{"name":"alice"}`, string(calls[0].Request))
+	assert.Equal(t, "null", string(calls[2].Request))
+	assert.Equal(t, "404", calls[2].Code)
+}
+
+func TestHTTPUnspecifiedAddr(t *testing.T) {
+	s := New([]Rule{{Method: "GET /users", Body: []byte(`[]`)}}, WithHTTP(":0"), WithMaxCalls(2))
+
+	if !assert.NoError(t, s.Start()) {
+		return
+	}
+
+	defer s.Close()
+
+	host, _, err := net.SplitHostPort(s.HTTPAddr())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.False(t, net.ParseIP(host).IsUnspecified())
+
+	for i := 0; i < 3; i++ {
+		res, err := http.Get(fmt.Sprintf("http://%s/users?page=%d", s.HTTPAddr(), i))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_ = res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+
+	// the oldest call is dropped
+	assert.Len(t, s.Calls("GET /users"), 2)
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	a, b := New(nil), New(nil)
+
+	r.Register("default/mock", a)
+	r.Register("default/mock", b)
+
+	// stale server doesn't remove its replacement
+	r.Remove("default/mock", a)
+
+	s, ok := r.Get("default/mock")
+	assert.True(t, ok)
+	assert.Equal(t, b, s)
+
+	r.Remove("default/mock", b)
+	r.Remove("default/mock", a)
+
+	_, ok = r.Get("default/mock")
+	assert.False(t, ok)
+}
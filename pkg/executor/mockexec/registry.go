@@ -0,0 +1,40 @@
+package mockexec
+
+import "sync"
+
+// Registry of running servers
+type Registry struct {
+	mu sync.Mutex
+	// key: namespace/name of mock service
+	servers map[string]*Server
+}
+
+func NewRegistry() *Registry {
+	return &Registry{servers: make(map[string]*Server)}
+}
+
+func (r *Registry) Register(key string, s *Server) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.servers[key] = s
+}
+
+func (r *Registry) Get(key string) (*Server, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.servers[key]
+
+	return s, ok
+}
+
+// Remove server if it's still registered under key
+func (r *Registry) Remove(key string, s *Server) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.servers[key] == s {
+		delete(r.servers, key)
+	}
+}
@@ -0,0 +1,72 @@
+package pluginexec
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+
+	"github.com/d7561985/karness/pkg/executor/pluginexec/pb"
+	"google.golang.org/grpc"
+)
+
+// MockConfig is config of MockPlugin
+type MockConfig struct {
+	// Code returned by Execute, required
+	Code string `json:"code"`
+}
+
+// MockPlugin requires MockConfig and echoes request body.
+// Scenario is returned in X-Scenario header.
+type MockPlugin struct {
+	pb.UnimplementedExecutorServer
+}
+
+func (m *MockPlugin) Describe(context.Context, *pb.DescribeRequest) (*pb.DescribeResponse, error) {
+	return &pb.DescribeResponse{Name: "mock", Version: "v0.0.1", Description: "echo request body"}, nil
+}
+
+func (m *MockPlugin) Validate(_ context.Context, req *pb.ValidateRequest) (*pb.ValidateResponse, error) {
+	var cfg MockConfig
+
+	if err := json.Unmarshal(req.Config, &cfg); err != nil {
+		return &pb.ValidateResponse{Errors: []string{err.Error()}}, nil
+	}
+
+	if cfg.Code == "" {
+		return &pb.ValidateResponse{Errors: []string{"code is required"}}, nil
+	}
+
+	return &pb.ValidateResponse{}, nil
+}
+
+func (m *MockPlugin) Execute(_ context.Context, req *pb.ExecuteRequest) (*pb.ExecuteResponse, error) {
+	var cfg MockConfig
+	_ = json.Unmarshal(req.Config, &cfg)
+
+	return &pb.ExecuteResponse{
+		Code: cfg.Code,
+		Body: req.Body,
+		Headers: map[string]*pb.HeaderValues{
+			"X-Scenario": {Values: []string{req.Scenario}},
+		},
+	}, nil
+}
+
+func CreateMockPlugin() (net.Listener, *grpc.Server) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := grpc.NewServer()
+	pb.RegisterExecutorServer(s, &MockPlugin{})
+
+	go func() {
+		if err := s.Serve(l); err != nil {
+			log.Fatalf("Server exited with error: %v", err)
+		}
+	}()
+
+	return l, s
+}
@@ -0,0 +1,123 @@
+package pluginexec
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/d7561985/karness/pkg/executor/pluginexec/pb"
+	"google.golang.org/grpc"
+)
+
+const (
+	// EnvAddr contains address which plugin started by karness should listen
+	EnvAddr = "KARNESS_PLUGIN_ADDR"
+
+	// execPrefix marks target as plugin binary path
+	execPrefix = "exec:"
+
+	startTimeout = 10 * time.Second
+	dialTimeout  = time.Second
+)
+
+// Client of out-of-process plugin
+type Client struct {
+	pb.ExecutorClient
+
+	// Info returned by plugin on start
+	Info *pb.DescribeResponse
+
+	cc  *grpc.ClientConn
+	cmd *exec.Cmd
+	dir string
+}
+
+// Open starts plugin binary when target is exec:/path/to/binary or dials target address otherwise
+func Open(ctx context.Context, target string) (*Client, error) {
+	if strings.HasPrefix(target, execPrefix) {
+		return Start(ctx, strings.TrimPrefix(target, execPrefix))
+	}
+
+	return Dial(ctx, target)
+}
+
+// Dial connects to running plugin, e.g. sidecar, and request its description
+func Dial(ctx context.Context, addr string) (*Client, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	cc, err := grpc.DialContext(dialCtx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return nil, fmt.Errorf("dial plugin %q: %w", addr, err)
+	}
+
+	c := &Client{ExecutorClient: pb.NewExecutorClient(cc), cc: cc}
+
+	if c.Info, err = c.Describe(ctx, &pb.DescribeRequest{}); err != nil {
+		_ = cc.Close()
+		return nil, fmt.Errorf("describe plugin %q: %w", addr, err)
+	}
+
+	return c, nil
+}
+
+// Start runs plugin binary which should listen unix socket passed with EnvAddr.
+// Plugin process is killed on Close.
+func Start(ctx context.Context, path string, args ...string) (*Client, error) {
+	dir, err := ioutil.TempDir("", "karness-plugin")
+	if err != nil {
+		return nil, err
+	}
+
+	addr := "unix://" + filepath.Join(dir, "plugin.sock")
+
+	cmd := exec.Command(path, args...)
+	cmd.Env = append(os.Environ(), EnvAddr+"="+addr)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err = cmd.Start(); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("start plugin %q: %w", path, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+
+	// wait until plugin starts listening
+	for {
+		c, err := Dial(ctx, addr)
+		if err == nil {
+			c.cmd, c.dir = cmd, dir
+			return c, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			_ = os.RemoveAll(dir)
+
+			return nil, fmt.Errorf("plugin %q didn't start: %w", path, err)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// Close connection and stop plugin process if it was started by Start
+func (c *Client) Close() error {
+	err := c.cc.Close()
+
+	if c.cmd != nil {
+		_ = c.cmd.Process.Kill()
+		_ = c.cmd.Wait()
+		_ = os.RemoveAll(c.dir)
+	}
+
+	return err
+}
@@ -0,0 +1,112 @@
+package pluginexec
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/d7561985/karness/pkg/executor/pluginexec/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+const envHelper = "KARNESS_TEST_PLUGIN"
+
+// TestHelperPlugin is not real test, it serves MockPlugin when test binary is started as plugin
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv(envHelper) != "1" {
+		return
+	}
+
+	if err := Serve("", &MockPlugin{}); err != nil {
+		os.Exit(1)
+	}
+}
+
+func TestDial(t *testing.T) {
+	l, srv := CreateMockPlugin()
+
+	defer l.Close()
+	defer srv.Stop()
+
+	c, err := Open(context.Background(), l.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer c.Close()
+
+	assert.Equal(t, "mock", c.Info.GetName())
+
+	tests := []struct {
+		name   string
+		config string
+		errors int
+	}{
+		{"ok", `{"code":"OK"}`, 0},
+		{"missing", `{}`, 1},
+		{"broken", `{`, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := c.Validate(context.Background(), &pb.ValidateRequest{Config: []byte(test.config)})
+			assert.NoError(t, err)
+			assert.Len(t, res.Errors, test.errors)
+		})
+	}
+
+	res, err := c.Execute(context.Background(), &pb.ExecuteRequest{
+		Config:   []byte(`{"code":"OK"}`),
+		Body:     []byte(`{"name":"hello"}`),
+		Scenario: "default/test",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", res.Code)
+	assert.JSONEq(t, `{"name":"hello"}`, string(res.Body))
+	assert.Equal(t, []string{"default/test"}, res.Headers["X-Scenario"].GetValues())
+}
+
+func TestStart(t *testing.T) {
+	os.Setenv(envHelper, "1")
+	defer os.Unsetenv(envHelper)
+
+	c, err := Start(context.Background(), os.Args[0], "-test.run=TestHelperPlugin")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "mock", c.Info.GetName())
+
+	res, err := c.Execute(context.Background(), &pb.ExecuteRequest{Config: []byte(`{"code":"OK"}`)})
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", res.Code)
+
+	assert.NoError(t, c.Close())
+
+	_, err = os.Stat(c.dir)
+	assert.True(t, os.IsNotExist(err), "socket directory is removed")
+}
+
+func TestRegistry(t *testing.T) {
+	l, srv := CreateMockPlugin()
+
+	defer l.Close()
+	defer srv.Stop()
+
+	c, err := Dial(context.Background(), l.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	Register("mock", c)
+
+	got, ok := Get("mock")
+	assert.True(t, ok)
+	assert.Equal(t, c, got)
+
+	CloseAll()
+
+	_, ok = Get("mock")
+	assert.False(t, ok)
+}
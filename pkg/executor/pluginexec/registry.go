@@ -0,0 +1,51 @@
+package pluginexec
+
+import (
+	"sync"
+
+	"k8s.io/klog/v2"
+)
+
+var (
+	mu      sync.RWMutex
+	plugins = make(map[string]*Client)
+)
+
+// Register makes plugin available for actions by name.
+// Previously registered plugin with the same name is closed.
+func Register(name string, c *Client) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if old, ok := plugins[name]; ok {
+		if err := old.Close(); err != nil {
+			klog.Errorf("close plugin %q: %v", name, err)
+		}
+	}
+
+	plugins[name] = c
+}
+
+// Get returns registered plugin
+func Get(name string) (*Client, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	c, ok := plugins[name]
+
+	return c, ok
+}
+
+// CloseAll closes and unregister all plugins
+func CloseAll() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for name, c := range plugins {
+		if err := c.Close(); err != nil {
+			klog.Errorf("close plugin %q: %v", name, err)
+		}
+
+		delete(plugins, name)
+	}
+}
@@ -0,0 +1,34 @@
+package pluginexec
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/d7561985/karness/pkg/executor/pluginexec/pb"
+	"google.golang.org/grpc"
+)
+
+// Serve is helper for plugin authors written in Go.
+// It listens address passed by karness with EnvAddr, or addr when plugin runs as sidecar, and blocks until server stops.
+func Serve(addr string, srv pb.ExecutorServer) error {
+	if v := os.Getenv(EnvAddr); v != "" {
+		addr = v
+	}
+
+	network := "tcp"
+	if strings.HasPrefix(addr, "unix://") {
+		network, addr = "unix", strings.TrimPrefix(addr, "unix://")
+	}
+
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		return fmt.Errorf("plugin listen %s %q: %w", network, addr, err)
+	}
+
+	s := grpc.NewServer()
+	pb.RegisterExecutorServer(s, srv)
+
+	return s.Serve(l)
+}
@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+)
+
+var (
+	ErrNoAction      = errors.New("action kind is not set")
+	ErrManyActions   = errors.New("only one action kind could be set")
+	ErrUnknownAction = errors.New("executor for action kind is not registered")
+)
+
+// Factory creates executor of particular action
+type Factory func(a v1alpha1.Action, env Env) (Executor, error)
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+// Register makes executor factory available for action kind.
+// Kind is json name of v1alpha1.Action field which turns on the action, e.g. grpc or http.
+// If Register is called twice with the same kind or if factory is nil, it panics.
+func Register(kind string, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if f == nil {
+		panic("executor: register factory is nil")
+	}
+
+	if _, dup := factories[kind]; dup {
+		panic("executor: register called twice for kind " + kind)
+	}
+
+	factories[kind] = f
+}
+
+// Kinds returns sorted list of registered action kinds
+func Kinds() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	res := make([]string, 0, len(factories))
+	for k := range factories {
+		res = append(res, k)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// Kind returns json name of the only non nil action field
+func Kind(a v1alpha1.Action) (string, error) {
+	var kinds []string
+
+	v := reflect.ValueOf(a)
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Ptr || f.IsNil() {
+			continue
+		}
+
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = strings.ToLower(t.Field(i).Name)
+		}
+
+		kinds = append(kinds, name)
+	}
+
+	switch len(kinds) {
+	case 0:
+		return "", ErrNoAction
+	case 1:
+		return kinds[0], nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrManyActions, strings.Join(kinds, ", "))
+	}
+}
+
+// New creates executor for action kind
+func New(kind string, a v1alpha1.Action, env Env) (Executor, error) {
+	mu.RLock()
+	f, ok := factories[kind]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAction, kind)
+	}
+
+	return f(a, env)
+}
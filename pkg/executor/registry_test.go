@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1"
+	"github.com/d7561985/karness/pkg/apis/karness/v1alpha1/models/action"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeExecutor struct {
+	code string
+}
+
+func (f fakeExecutor) Call(context.Context) (*Result, error) {
+	return &Result{Code: f.code}, nil
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      v1alpha1.Action
+		want    string
+		wantErr error
+	}{
+		{"grpc", v1alpha1.Action{GRPC: &action.GRPC{}}, "grpc", nil},
+		{"http", v1alpha1.Action{HTTP: &action.HTTP{}}, "http", nil},
+		{"none", v1alpha1.Action{Name: "X"}, "", ErrNoAction},
+		{"many", v1alpha1.Action{GRPC: &action.GRPC{}, HTTP: &action.HTTP{}}, "", ErrManyActions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Kind(tt.in)
+			assert.True(t, errors.Is(err, tt.wantErr), "error %v", err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	// registry is global, repeated run registers kind again
+	defer func() {
+		mu.Lock()
+		delete(factories, "fake")
+		mu.Unlock()
+	}()
+
+	Register("fake", func(a v1alpha1.Action, env Env) (Executor, error) {
+		return fakeExecutor{code: a.Name}, nil
+	})
+
+	assert.Contains(t, Kinds(), "fake")
+
+	assert.Panics(t, func() {
+		Register("fake", func(v1alpha1.Action, Env) (Executor, error) { return nil, nil })
+	})
+
+	e, err := New("fake", v1alpha1.Action{Name: "X"}, Env{})
+	assert.NoError(t, err)
+
+	res, err := e.Call(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "X", res.Code)
+
+	_, err = New("unknown", v1alpha1.Action{}, Env{})
+	assert.True(t, errors.Is(err, ErrUnknownAction))
+}
+
+func TestEnvService(t *testing.T) {
+	env := Env{Services: Services{"clock": time.Second}}
+
+	assert.Equal(t, time.Second, env.Service("clock"))
+	assert.Nil(t, env.Service("missing"))
+	assert.Nil(t, Env{}.Service("clock"))
+}
@@ -1,35 +1,39 @@
-package harness
+package executor
 
 import (
 	"bytes"
-	"context"
 	"errors"
 	"fmt"
+	"time"
+
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/client-go/util/jsonpath"
 )
 
-type Action interface {
-	Call(ctx context.Context) (*ActionResult, error)
-}
-
 var (
 	ErrNoKey       = errors.New("provided key not exists in result")
 	ErrBadJsonPath = errors.New("bad json path formula")
 )
 
-type ActionResult struct {
+// Result of action
+type Result struct {
 	Code string
 	Body []byte
+
+	// Header contains response headers or metadata when action supports it
+	Header map[string][]string
+
+	// Latency of action call
+	Latency time.Duration
 }
 
-func OK() *ActionResult {
-	return &ActionResult{Code: "OK"}
+func OK() *Result {
+	return &Result{Code: "OK"}
 }
 
 // GetKeyValue look up key in our json representation
 // every time perform JSON marshaling. This is too slow!!!
-func (a *ActionResult) GetKeyValue(jsonPath string) (string, error) {
+func (a *Result) GetKeyValue(jsonPath string) (string, error) {
 	var tmp interface{}
 	if err := json.Unmarshal(a.Body, &tmp); err != nil {
 		return "", fmt.Errorf("can't unmarshal body: %w", err)
@@ -49,6 +53,5 @@ func (a *ActionResult) GetKeyValue(jsonPath string) (string, error) {
 		return "", ErrBadJsonPath
 	}
 
-	fmt.Println(">>>", buf.String())
 	return buf.String(), nil
 }
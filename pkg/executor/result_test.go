@@ -1,4 +1,4 @@
-package harness
+package executor
 
 import (
 	"bytes"
@@ -10,7 +10,7 @@ import (
 	"testing"
 )
 
-func TestActionResult_GetKeyValue(t *testing.T) {
+func TestResult_GetKeyValue(t *testing.T) {
 	type fields struct {
 		Code string
 		Body []byte
@@ -48,7 +48,7 @@ func TestActionResult_GetKeyValue(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := &ActionResult{
+			a := &Result{
 				Code: tt.fields.Code,
 				Body: tt.fields.Body,
 			}
@@ -0,0 +1,149 @@
+package sqlexec
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	// drivers available for actions
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+var ErrUnknownDriver = errors.New("unknown sql driver")
+
+type Option func(*Config)
+
+type Config struct {
+	maxRows int
+}
+
+// WithMaxRows limits number of returned rows, 0 means no limit
+func WithMaxRows(n int) Option {
+	return func(c *Config) {
+		c.maxRows = n
+	}
+}
+
+type Request struct {
+	// Driver registered sql driver: postgres, mysql
+	Driver string
+	// DSN connection string
+	DSN   string
+	Query string
+	Args  []interface{}
+	// Exec runs statement which doesn't return rows
+	Exec bool
+}
+
+type service struct {
+	Config
+}
+
+func New(opt ...Option) *service {
+	c := Config{}
+
+	for _, o := range opt {
+		o(&c)
+	}
+
+	return &service{Config: c}
+}
+
+// Validate checks that driver is registered
+func Validate(driver string) error {
+	for _, d := range sql.Drivers() {
+		if d == driver {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownDriver, driver)
+}
+
+// Call returns rows as JSON array of objects with column name keys.
+// Statement executed with Exec returns object with rows_affected.
+func (s *service) Call(ctx context.Context, r Request) ([]byte, error) {
+	if err := Validate(r.Driver); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(r.Driver, r.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("sql open: %w", err)
+	}
+
+	defer db.Close()
+
+	if r.Exec {
+		res, err := db.ExecContext(ctx, r.Query, r.Args...)
+		if err != nil {
+			return nil, fmt.Errorf("sql exec: %w", err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("sql rows affected: %w", err)
+		}
+
+		return json.Marshal(map[string]int64{"rows_affected": n})
+	}
+
+	rows, err := db.QueryContext(ctx, r.Query, r.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("sql query: %w", err)
+	}
+
+	defer rows.Close()
+
+	res, err := s.scan(rows)
+	if err != nil {
+		return nil, fmt.Errorf("sql scan: %w", err)
+	}
+
+	return json.Marshal(res)
+}
+
+func (s *service) scan(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]map[string]interface{}, 0)
+
+	for rows.Next() {
+		if s.maxRows > 0 && len(res) >= s.maxRows {
+			break
+		}
+
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+
+		if err = rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+
+		for i, column := range columns {
+			// text columns of some drivers are returned as bytes
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+				continue
+			}
+
+			row[column] = values[i]
+		}
+
+		res = append(res, row)
+	}
+
+	return res, rows.Err()
+}
@@ -0,0 +1,64 @@
+package sqlexec
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCall(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db")
+	ctx := context.Background()
+	s := New()
+
+	_, err := s.Call(ctx, Request{
+		Driver: "sqlite3",
+		DSN:    dsn,
+		Query:  `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, balance REAL)`,
+		Exec:   true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	res, err := s.Call(ctx, Request{
+		Driver: "sqlite3",
+		DSN:    dsn,
+		Query:  `INSERT INTO users (name, balance) VALUES (?, ?), (?, ?)`,
+		Args:   []interface{}{"alice", 10.5, "bob", 0},
+		Exec:   true,
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"rows_affected":2}`, string(res))
+
+	tests := []struct {
+		name   string
+		s      *service
+		query  string
+		args   []interface{}
+		expect string
+	}{
+		{"all", s, `SELECT id, name, balance FROM users ORDER BY id`, nil,
+			`[{"id":1,"name":"alice","balance":10.5},{"id":2,"name":"bob","balance":0}]`},
+		{"args", s, `SELECT name FROM users WHERE balance > ?`, []interface{}{"1"}, `[{"name":"alice"}]`},
+		{"empty", s, `SELECT name FROM users WHERE id = 100`, nil, `[]`},
+		{"max-rows", New(WithMaxRows(1)), `SELECT id FROM users ORDER BY id`, nil, `[{"id":1}]`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := test.s.Call(ctx, Request{Driver: "sqlite3", DSN: dsn, Query: test.query, Args: test.args})
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expect, string(res))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("postgres"))
+	assert.NoError(t, Validate("mysql"))
+	assert.ErrorIs(t, Validate("oracle"), ErrUnknownDriver)
+}
@@ -0,0 +1,82 @@
+package starlarkexec
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// cryptoModule returns hex encoded digests
+var cryptoModule = &starlarkstruct.Module{
+	Name: "crypto",
+	Members: starlark.StringDict{
+		"md5":         digest("crypto.md5", md5.New),
+		"sha1":        digest("crypto.sha1", sha1.New),
+		"sha256":      digest("crypto.sha256", sha256.New),
+		"hmac_sha256": starlark.NewBuiltin("crypto.hmac_sha256", hmacSHA256),
+	},
+}
+
+var base64Module = &starlarkstruct.Module{
+	Name: "base64",
+	Members: starlark.StringDict{
+		"encode": starlark.NewBuiltin("base64.encode", base64Encode),
+		"decode": starlark.NewBuiltin("base64.decode", base64Decode),
+	},
+}
+
+func digest(name string, h func() hash.Hash) *starlark.Builtin {
+	return starlark.NewBuiltin(name, func(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var data string
+		if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &data); err != nil {
+			return nil, err
+		}
+
+		d := h()
+		d.Write([]byte(data))
+
+		return starlark.String(hex.EncodeToString(d.Sum(nil))), nil
+	})
+}
+
+func hmacSHA256(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var key, data string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &key, &data); err != nil {
+		return nil, err
+	}
+
+	m := hmac.New(sha256.New, []byte(key))
+	m.Write([]byte(data))
+
+	return starlark.String(hex.EncodeToString(m.Sum(nil))), nil
+}
+
+func base64Encode(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var data string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &data); err != nil {
+		return nil, err
+	}
+
+	return starlark.String(base64.StdEncoding.EncodeToString([]byte(data))), nil
+}
+
+func base64Decode(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var data string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &data); err != nil {
+		return nil, err
+	}
+
+	res, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return starlark.String(res), nil
+}
@@ -0,0 +1,237 @@
+package starlarkexec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+const (
+	defaultMaxSteps = 1_000_000
+
+	// globals which script sets to form result
+	codeGlobal   = "code"
+	bodyGlobal   = "body"
+	headerGlobal = "header"
+
+	defaultCode = "OK"
+)
+
+// fileOptions of scripts, they are flat: allow if/for/while at top-level and code reassignment
+var fileOptions = &syntax.FileOptions{
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+}
+
+// Store is scenario variable store, sync.Map satisfies it
+type Store interface {
+	Load(key interface{}) (interface{}, bool)
+	Store(key, value interface{})
+}
+
+type Option func(*Config)
+
+type Config struct {
+	maxSteps uint64
+}
+
+// WithMaxSteps limits script execution, 1M steps by default, 0 removes limit
+func WithMaxSteps(n uint64) Option {
+	return func(c *Config) {
+		c.maxSteps = n
+	}
+}
+
+type Request struct {
+	// Name of script used in error messages
+	Name   string
+	Script string
+	// Input available as input global
+	Input []byte
+	Vars  Store
+}
+
+// Result of script formed from code, body and header globals
+type Result struct {
+	Code   string
+	Body   []byte
+	Header map[string][]string
+}
+
+type service struct {
+	Config
+}
+
+func New(opt ...Option) *service {
+	c := Config{maxSteps: defaultMaxSteps}
+
+	for _, o := range opt {
+		o(&c)
+	}
+
+	return &service{Config: c}
+}
+
+// Run executes script. Action body is available as input string, scenario variables
+// are accessed by vars.get(name, default=None) and vars.set(name, value), json, crypto
+// and base64 are helper modules.
+// Script sets code (OK by default), body and header globals to form result. String body
+// is returned as is, other values are encoded to JSON. Header is dict of string values.
+func (s *service) Run(ctx context.Context, r Request) (*Result, error) {
+	if r.Vars == nil {
+		r.Vars = &sync.Map{}
+	}
+
+	thread := &starlark.Thread{Name: r.Name}
+	thread.SetMaxExecutionSteps(s.maxSteps)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			thread.Cancel(ctx.Err().Error())
+		case <-done:
+		}
+	}()
+
+	globals, err := starlark.ExecFileOptions(fileOptions, thread, filename(r.Name), r.Script, predeclared(r.Input, r.Vars))
+	if err != nil {
+		return nil, fmt.Errorf("starlark %s: %w", r.Name, err)
+	}
+
+	return result(thread, globals)
+}
+
+// Compile checks script syntax and resolves its names without execution
+func Compile(name, script string) error {
+	predecl := predeclared(nil, nil)
+
+	_, _, err := starlark.SourceProgramOptions(fileOptions, filename(name), script, predecl.Has)
+	if err != nil {
+		return fmt.Errorf("starlark %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func filename(name string) string {
+	return name + ".star"
+}
+
+func predeclared(input []byte, vars Store) starlark.StringDict {
+	return starlark.StringDict{
+		"input":  starlark.String(input),
+		"vars":   varsModule(vars),
+		"json":   json.Module,
+		"crypto": cryptoModule,
+		"base64": base64Module,
+	}
+}
+
+func result(thread *starlark.Thread, globals starlark.StringDict) (*Result, error) {
+	res := &Result{Code: defaultCode}
+
+	if v, ok := globals[codeGlobal]; ok {
+		res.Code = str(v)
+	}
+
+	if v, ok := globals[bodyGlobal]; ok {
+		b, err := encode(thread, v)
+		if err != nil {
+			return nil, fmt.Errorf("starlark body: %w", err)
+		}
+
+		res.Body = []byte(b)
+	}
+
+	if v, ok := globals[headerGlobal]; ok {
+		d, ok := v.(*starlark.Dict)
+		if !ok {
+			return nil, fmt.Errorf("starlark header should be dict, got %s", v.Type())
+		}
+
+		res.Header = make(map[string][]string, d.Len())
+
+		for _, item := range d.Items() {
+			res.Header[str(item[0])] = []string{str(item[1])}
+		}
+	}
+
+	return res, nil
+}
+
+func varsModule(store Store) *starlarkstruct.Module {
+	return &starlarkstruct.Module{
+		Name: "vars",
+		Members: starlark.StringDict{
+			"get": starlark.NewBuiltin("vars.get", func(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+				var (
+					name string
+					def  starlark.Value = starlark.None
+				)
+
+				if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "name", &name, "default?", &def); err != nil {
+					return nil, err
+				}
+
+				v, ok := store.Load(name)
+				if !ok {
+					return def, nil
+				}
+
+				return starlark.String(fmt.Sprint(v)), nil
+			}),
+			"set": starlark.NewBuiltin("vars.set", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+				var (
+					name  string
+					value starlark.Value
+				)
+
+				if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "name", &name, "value", &value); err != nil {
+					return nil, err
+				}
+
+				// variables are strings, other values are stored as JSON
+				v, err := encode(thread, value)
+				if err != nil {
+					return nil, err
+				}
+
+				store.Store(name, v)
+
+				return starlark.None, nil
+			}),
+		},
+	}
+}
+
+// encode returns strings as is and JSON representation of other values
+func encode(thread *starlark.Thread, v starlark.Value) (string, error) {
+	if s, ok := starlark.AsString(v); ok {
+		return s, nil
+	}
+
+	res, err := starlark.Call(thread, json.Module.Members["encode"], starlark.Tuple{v}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return str(res), nil
+}
+
+// str is string content or printed value
+func str(v starlark.Value) string {
+	if s, ok := starlark.AsString(v); ok {
+		return s
+	}
+
+	return v.String()
+}
@@ -0,0 +1,131 @@
+package starlarkexec
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.starlark.net/resolve"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		input  string
+		vars   map[string]string
+		want   *Result
+		stored map[string]string
+		err    bool
+	}{
+		{
+			name:   "default",
+			script: `x = 1`,
+			want:   &Result{Code: "OK"},
+		},
+		{
+			name:   "string body",
+			script: `body = "hello " + input`,
+			input:  "world",
+			want:   &Result{Code: "OK", Body: []byte("hello world")},
+		},
+		{
+			name: "json body",
+			script: `
+req = json.decode(input)
+code = "Created" if req["id"] > 1 else "OK"
+body = {"id": req["id"], "token": vars.get("token")}
+header = {"X-Sign": crypto.sha256(input)}
+`,
+			input: `{"id":2}`,
+			vars:  map[string]string{"token": "abc"},
+			want: &Result{
+				Code:   "Created",
+				Body:   []byte(`{"id":2,"token":"abc"}`),
+				Header: map[string][]string{"X-Sign": {"9e7e65453739bbc64ba155eed18a37bd5fb1196c7f29ded1da660b94414d7ad8"}},
+			},
+		},
+		{
+			name: "set vars",
+			script: `
+vars.set("a", base64.encode("abc"))
+vars.set("b", [1, 2])
+vars.set("c", vars.get("missing", "default"))
+`,
+			want:   &Result{Code: "OK"},
+			stored: map[string]string{"a": "YWJj", "b": "[1,2]", "c": "default"},
+		},
+		{
+			name:   "top level control flow",
+			script: "n = 0\nfor i in range(3):\n    n += i\nif n == 3:\n    code = str(n)\n",
+			want:   &Result{Code: "3"},
+		},
+		{
+			name:   "runtime error",
+			script: `fail("boom")`,
+			err:    true,
+		},
+		{
+			name:   "bad header",
+			script: `header = 1`,
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &sync.Map{}
+			for k, v := range test.vars {
+				store.Store(k, v)
+			}
+
+			res, err := New().Run(context.Background(), Request{
+				Name:   test.name,
+				Script: test.script,
+				Input:  []byte(test.input),
+				Vars:   store,
+			})
+
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, test.want, res)
+
+			for k, v := range test.stored {
+				got, _ := store.Load(k)
+				assert.Equal(t, v, got, k)
+			}
+		})
+	}
+}
+
+func TestRunLimits(t *testing.T) {
+	script := "def loop():\n    for i in range(1000000000):\n        pass\nloop()\n"
+
+	_, err := New(WithMaxSteps(1000)).Run(context.Background(), Request{Name: "steps", Script: script})
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = New(WithMaxSteps(0)).Run(ctx, Request{Name: "cancel", Script: script})
+	assert.Error(t, err)
+}
+
+func TestCompile(t *testing.T) {
+	assert.NoError(t, Compile("ok", `body = json.encode({"a": vars.get("a")})`))
+	assert.Error(t, Compile("syntax", `body = (`))
+	assert.Error(t, Compile("undefined", `body = unknown`))
+	assert.NoError(t, Compile("flat", "code = \"A\"\nif vars.get(\"a\"):\n    code = \"B\"\n"))
+
+	// options are set per script, global options of other starlark users aren't changed
+	assert.False(t, resolve.AllowGlobalReassign)
+}
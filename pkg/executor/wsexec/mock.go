@@ -0,0 +1,40 @@
+package wsexec
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gorilla/websocket"
+)
+
+// CreateMockServer pushes messages to every connected client and echoes text messages it receives.
+// Websocket url of server is "ws" + strings.TrimPrefix(srv.URL, "http").
+func CreateMockServer(push ...string) *httptest.Server {
+	var upgrader websocket.Upgrader
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, http.Header{"X-Mock": {"websocket"}})
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		for _, m := range push {
+			if err = conn.WriteMessage(websocket.TextMessage, []byte(m)); err != nil {
+				return
+			}
+		}
+
+		for {
+			typ, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			if err = conn.WriteMessage(typ, data); err != nil {
+				return
+			}
+		}
+	}))
+}
@@ -0,0 +1,147 @@
+package wsexec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const closeTimeout = time.Second
+
+// Matcher stops collecting when it returns true for received message
+type Matcher func(msg []byte) bool
+
+type Option func(*Config)
+
+type Config struct {
+	dialer *websocket.Dialer
+}
+
+// WithDialer replaces default dialer, for example with custom tls config
+func WithDialer(d *websocket.Dialer) Option {
+	return func(c *Config) {
+		c.dialer = d
+	}
+}
+
+// Request describes message exchange, collecting lasts until ctx is done, limit is reached or match hits
+type Request struct {
+	// Addr ws or wss url
+	Addr   string
+	Header map[string]string
+	// Messages are sent as text in order right after connection
+	Messages [][]byte
+	// Limit of collected messages, 0 is unlimited
+	Limit int
+	Match Matcher
+}
+
+type service struct {
+	Config
+}
+
+func New(opt ...Option) *service {
+	c := Config{dialer: websocket.DefaultDialer}
+
+	for _, o := range opt {
+		o(&c)
+	}
+
+	return &service{Config: c}
+}
+
+// Exchange returns JSON array of received messages, handshake response headers and whether matcher hit.
+// JSON text messages are embedded as is, other text messages become strings and binary ones base64 strings.
+func (s *service) Exchange(ctx context.Context, r Request) ([]byte, http.Header, bool, error) {
+	header := make(http.Header, len(r.Header))
+	for k, v := range r.Header {
+		header.Set(k, v)
+	}
+
+	conn, resp, err := s.dialer.DialContext(ctx, r.Addr, header)
+	if err != nil {
+		if resp != nil {
+			err = fmt.Errorf("%w: handshake status %d", err, resp.StatusCode)
+		}
+
+		return nil, nil, false, fmt.Errorf("websocket dial %q: %w", r.Addr, err)
+	}
+
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// unblock read when ctx is done
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	for _, m := range r.Messages {
+		if err = conn.WriteMessage(websocket.TextMessage, m); err != nil {
+			return nil, nil, false, fmt.Errorf("websocket write: %w", err)
+		}
+	}
+
+	res, matched, err := collect(ctx, conn, r)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(closeTimeout))
+
+	body, err := json.Marshal(res)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return body, resp.Header, matched, nil
+}
+
+func collect(ctx context.Context, conn *websocket.Conn, r Request) ([]json.RawMessage, bool, error) {
+	res := make([]json.RawMessage, 0)
+
+	for r.Limit <= 0 || len(res) < r.Limit {
+		typ, data, err := conn.ReadMessage()
+
+		switch {
+		case ctx.Err() != nil, websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway):
+			return res, false, nil
+		case err != nil:
+			return nil, false, fmt.Errorf("websocket read: %w", err)
+		}
+
+		msg, err := encode(typ, data)
+		if err != nil {
+			return nil, false, err
+		}
+
+		res = append(res, msg)
+
+		if r.Match != nil && r.Match(data) {
+			return res, true, nil
+		}
+	}
+
+	return res, false, nil
+}
+
+func encode(typ int, data []byte) (json.RawMessage, error) {
+	switch {
+	case typ == websocket.BinaryMessage:
+		return json.Marshal(data)
+	case json.Valid(data):
+		return data, nil
+	default:
+		return json.Marshal(string(data))
+	}
+}
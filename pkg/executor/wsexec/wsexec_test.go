@@ -0,0 +1,70 @@
+package wsexec
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchange(t *testing.T) {
+	tests := []struct {
+		name    string
+		push    []string
+		req     Request
+		body    string
+		matched bool
+	}{
+		{
+			name: "limit",
+			push: []string{`{"n":1}`, `hello`, `{"n":2}`},
+			req:  Request{Limit: 2},
+			body: `[{"n":1},"hello"]`,
+		},
+		{
+			name:    "match echo",
+			push:    []string{`{"n":1}`},
+			req:     Request{Messages: [][]byte{[]byte(`{"ping":1}`)}, Match: func(msg []byte) bool { return strings.Contains(string(msg), "ping") }},
+			body:    `[{"n":1},{"ping":1}]`,
+			matched: true,
+		},
+		{
+			name: "duration",
+			push: []string{`{"n":1}`},
+			req:  Request{Match: func(msg []byte) bool { return false }},
+			body: `[{"n":1}]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := CreateMockServer(test.push...)
+			defer srv.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			test.req.Addr = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+			body, header, matched, err := New().Exchange(ctx, test.req)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, test.body, string(body))
+			assert.Equal(t, test.matched, matched)
+			assert.Equal(t, "websocket", header.Get("X-Mock"))
+		})
+	}
+}
+
+func TestExchangeDialError(t *testing.T) {
+	srv := CreateMockServer()
+	addr := "ws" + strings.TrimPrefix(srv.URL, "http")
+	srv.Close()
+
+	_, _, _, err := New().Exchange(context.Background(), Request{Addr: addr})
+	assert.Error(t, err)
+}